@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+const configUsage = `usage: api config <command>
+
+commands:
+  print [--redacted]   tampilkan konfigurasi efektif (default + CONFIG_FILE + env) dalam YAML`
+
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("%s", configUsage)
+	}
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	redact := fs.Bool("redacted", false, "sensor secret (JWT secret, password, token, password di DSN)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.Read(os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	out := cfg
+	if *redact {
+		out = cfg.Redacted()
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	// hasil tetap dicetak, tapi exit code menandakan server tidak akan mau start dengan config ini
+	return cfg.Validate()
+}
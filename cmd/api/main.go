@@ -1,26 +1,148 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
+	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/oktaharis/uji-teknis-godigi/internal/config"
 	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/jobs"
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
+	"github.com/oktaharis/uji-teknis-godigi/internal/metrics"
+	"github.com/oktaharis/uji-teknis-godigi/internal/migrate"
 	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+	"github.com/oktaharis/uji-teknis-godigi/internal/server"
+	"github.com/oktaharis/uji-teknis-godigi/internal/tracing"
+	"gorm.io/gorm"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	cfg := config.Load()
-	db := database.Connect(cfg)
+	// config print tetap jalan walau config tidak valid, supaya bisa dipakai untuk mencari masalahnya
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	r := routes.SetupRouter(cfg, db)
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err) // logger belum dikonfigurasi; satu masalah per baris
+		os.Exit(1)
+	}
+	slog.SetDefault(logging.New(cfg, os.Stdout))
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			slog.Error("migrate failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := runSeed(cfg, os.Args[2:]); err != nil {
+			slog.Error("seed failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	log.Printf("server running on :%s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Println("failed to start:", err)
+	if err := serve(cfg); err != nil {
+		slog.Error("server stopped", "error", err)
 		os.Exit(1)
 	}
 }
+
+// serve menjalankan API server sampai SIGINT/SIGTERM atau gagal start. Saat berhenti: server di-drain,
+// worker dihentikan, pool database ditutup lalu span di-flush, dalam urutan itu.
+func serve(cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("tracing shutdown failed", "error", err)
+		}
+	}()
+
+	db, err := database.Connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+
+	if cfg.DBAutoMigrate {
+		if err := migrateUp(cfg, db); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	m := metrics.New()
+	api := server.New(cfg, ":"+cfg.Port, routes.SetupRouter(cfg, db, m))
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		if err := api.UseTLS(ctx, cfg.TLSCertFile, cfg.TLSKeyFile); err != nil {
+			return err
+		}
+	}
+
+	// Semua goroutine latar berhenti lewat ctx dan ditunggu sebelum database ditutup.
+	var wg sync.WaitGroup
+	wg.Go(func() { jobs.RunTrashPurger(ctx, cfg, db) })
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		ms := server.New(cfg, cfg.MetricsAddr, mux)
+		ms.DrainDelay = 0 // scraper tidak perlu di-drain
+		wg.Go(func() {
+			slog.Info("metrics server running", "addr", cfg.MetricsAddr)
+			if err := ms.Run(ctx); err != nil {
+				slog.Error("metrics server stopped", "error", err)
+			}
+		})
+	}
+
+	slog.Info("server running", "port", cfg.Port, "env", cfg.AppEnv, "tls", api.HTTP.TLSConfig != nil)
+	err = api.Run(ctx)
+	stop() // kalau api gagal start, goroutine latar juga dihentikan
+	wg.Wait()
+	return err
+}
+
+// migrateUp menjalankan migration yang pending (DB_AUTO_MIGRATE).
+func migrateUp(cfg *config.Config, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	m, err := migrate.New(sqlDB, database.Dialect(cfg.DBDSN))
+	if err != nil {
+		return err
+	}
+	ran, err := m.Up(context.Background())
+	for _, mig := range ran {
+		slog.Info("migration applied", "version", mig.Version, "name", mig.Name)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/migrate"
+)
+
+const migrateUsage = `usage: api migrate <command>
+
+commands:
+  up              jalankan semua migration yang pending
+  down [n]        rollback n migration terakhir (default 1)
+  status          tampilkan status setiap migration
+  create <name>   buat file up/down baru untuk semua dialect di ` + migrate.Dir
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	// create tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return fmt.Errorf("usage: api migrate create <name>")
+		}
+		files, err := migrate.Create(migrate.Dir, args[1])
+		for _, f := range files {
+			fmt.Println("created", f)
+		}
+		return err
+	}
+
+	m, err := newMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		ran, err := m.Up(ctx)
+		for _, mig := range ran {
+			fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		ran, err := m.Down(ctx, steps)
+		for _, mig := range ran {
+			fmt.Printf("rolled back %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "status":
+		sts, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, st := range sts {
+			at := "-"
+			if st.AppliedAt != nil {
+				at = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, st.State, at)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+	}
+	return nil
+}
+
+func newMigrator(cfg *config.Config) (*migrate.Migrator, error) {
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, database.Dialect(cfg.DBDSN))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/seed"
+)
+
+const seedUsage = `usage: api seed <command> [flags]
+
+commands:
+  dataset [-file path]   muat dataset leads/deals bawaan (atau file lain), aman dijalankan ulang
+  demo [flags]           buat data sintetis: -users N -leads N -projects N -password P -seed S
+  admin                  buat/promosikan admin awal dari ADMIN_EMAIL, ADMIN_PASSWORD, ADMIN_NAME
+  all                    dataset + admin
+
+Migration yang pending ikut dijalankan kecuali DB_AUTO_MIGRATE=false.`
+
+func runSeed(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", seedUsage)
+	}
+	cmd, args := args[0], args[1:]
+
+	connect := func() (*gorm.DB, error) {
+		db, err := database.Connect(context.Background(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.DBAutoMigrate {
+			if err := migrateUp(cfg, db); err != nil {
+				return nil, err
+			}
+		}
+		return db, nil
+	}
+
+	switch cmd {
+	case "dataset":
+		fs := flag.NewFlagSet("seed dataset", flag.ContinueOnError)
+		file := fs.String("file", "", "file SQL berisi INSERT (default: dataset bawaan)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		db, err := connect()
+		if err != nil {
+			return err
+		}
+		return seedDataset(cfg, db, *file)
+	case "demo":
+		fs := flag.NewFlagSet("seed demo", flag.ContinueOnError)
+		opt := seed.DemoOptions{}
+		fs.IntVar(&opt.Users, "users", 10, "jumlah user")
+		fs.IntVar(&opt.Leads, "leads", 100, "jumlah lead (deal dibuat untuk lead Qualified/Won/Lost)")
+		fs.IntVar(&opt.Projects, "projects", 20, "jumlah project")
+		fs.StringVar(&opt.UserPassword, "password", "password123", "password semua user demo")
+		fs.Int64Var(&opt.Seed, "seed", time.Now().UnixNano(), "seed random, isi untuk hasil yang bisa diulang")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		db, err := connect()
+		if err != nil {
+			return err
+		}
+		res, err := seed.Demo(db, opt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("demo data: %d users, %d leads, %d deals, %d projects\n", res.Users, res.Leads, res.Deals, res.Projects)
+		return nil
+	case "admin", "all":
+		db, err := connect()
+		if err != nil {
+			return err
+		}
+		if cmd == "all" {
+			if err := seedDataset(cfg, db, ""); err != nil {
+				return err
+			}
+		}
+		return seedAdmin(cfg, db)
+	default:
+		return fmt.Errorf("unknown seed command %q\n\n%s", cmd, seedUsage)
+	}
+}
+
+func seedDataset(cfg *config.Config, db *gorm.DB, file string) error {
+	dialect := database.Dialect(cfg.DBDSN)
+
+	var res seed.DatasetResult
+	var err error
+	if file == "" {
+		res, err = seed.Dataset(db, dialect)
+	} else {
+		f, ferr := os.Open(file)
+		if ferr != nil {
+			return ferr
+		}
+		defer f.Close()
+		res, err = seed.LoadDataset(db, dialect, f)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("dataset: %d statements, %d rows inserted (%d already present)\n",
+		res.Statements, res.Inserted, int64(res.Statements)-res.Inserted)
+	return nil
+}
+
+func seedAdmin(cfg *config.Config, db *gorm.DB) error {
+	u, created, err := seed.EnsureAdmin(db, cfg.AdminName, cfg.AdminEmail, cfg.AdminPassword)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("admin created: %s (id %d)\n", u.Email, u.ID)
+	} else {
+		fmt.Printf("existing user promoted to active admin: %s (id %d), password unchanged\n", u.Email, u.ID)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+// Package apitest harness untuk test HTTP: router dari routes.SetupRouter di atas database SQLite
+// sekali pakai (file di t.TempDir()) yang sudah dimigrasi dan diisi fixture.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
+	"github.com/oktaharis/uji-teknis-godigi/internal/metrics"
+	"github.com/oktaharis/uji-teknis-godigi/internal/migrate"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+)
+
+// Password semua user fixture.
+const Password = "password123"
+
+// Harness satu router + database untuk satu test.
+type Harness struct {
+	T      *testing.T
+	Cfg    *config.Config
+	DB     *gorm.DB
+	Router *gin.Engine
+	Logs   *bytes.Buffer // output slog (JSON) selama test, lihat LogLines
+
+	// Fixture: satu admin aktif, satu user biasa, satu lead (dengan satu deal) dan satu project.
+	Admin   models.User
+	User    models.User
+	Lead    models.Lead
+	Project models.Project
+
+	tokens map[uint]string
+}
+
+// New menyiapkan database baru, menjalankan semua migration, mengisi fixture dan membangun router.
+func New(t *testing.T) *Harness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logs := &bytes.Buffer{}
+	slog.SetDefault(logging.NewJSON(logs, slog.LevelInfo)) // log tidak perlu di output test
+
+	cfg := config.Default()
+	cfg.AppEnv = "test"
+	cfg.DBDSN = "sqlite:" + filepath.Join(t.TempDir(), "test.db")
+	cfg.JWTSecret = "test-secret"
+	cfg.RateLimit.Enabled = false // test rate limit menyalakannya sendiri
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("db connect: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	m, err := migrate.New(sqlDB, database.SQLite)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	h := &Harness{T: t, Cfg: cfg, DB: db, Router: routes.SetupRouter(cfg, db, metrics.New()), Logs: logs, tokens: map[uint]string{}}
+	h.Admin = h.CreateUser("Admin", "admin@test.local", "admin")
+	h.User = h.CreateUser("Budi", "budi@test.local", "user")
+	h.Lead = h.CreateLead("PT Maju Jaya")
+	h.Project = h.CreateProject("Website Revamp", &h.User.ID)
+	return h
+}
+
+// LogLines baris log yang sudah ditulis, masing-masing sebagai objek JSON.
+func (h *Harness) LogLines() []map[string]interface{} {
+	h.T.Helper()
+	var out []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(h.Logs.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(line, &m); err != nil {
+			h.T.Fatalf("log line is not JSON: %s", line)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// CreateUser fixture user aktif dengan password Password.
+func (h *Harness) CreateUser(name, email, role string) models.User {
+	h.T.Helper()
+	hash, err := auth.HashPassword(Password)
+	if err != nil {
+		h.T.Fatalf("hash password: %v", err)
+	}
+	u := models.User{Name: name, Email: email, PasswordHash: hash, Role: role, Status: models.UserStatusActive}
+	h.create(&u)
+	return u
+}
+
+// CreateLead fixture lead beserta satu deal won.
+func (h *Harness) CreateLead(company string) models.Lead {
+	h.T.Helper()
+	status, source := "qualified", "website"
+	email := strings.ToLower(strings.ReplaceAll(company, " ", "")) + "@example.com"
+	l := models.Lead{CompanyName: company, ContactName: "Siti", Email: email, Status: &status, Source: &source}
+	h.create(&l)
+	h.create(&models.Deal{LeadID: l.LeadID, AmountIDR: 50_000_000, Currency: "IDR", TermMonths: 12, Stage: "won",
+		ClosedAt: time.Now()})
+	return l
+}
+
+// CreateProject fixture project berstatus planned.
+func (h *Harness) CreateProject(name string, owner *uint) models.Project {
+	h.T.Helper()
+	p := models.Project{Name: name, Status: "planned", OwnerUserID: owner}
+	h.create(&p)
+	return p
+}
+
+func (h *Harness) create(v interface{}) {
+	h.T.Helper()
+	if err := h.DB.Create(v).Error; err != nil {
+		h.T.Fatalf("create fixture %T: %v", v, err)
+	}
+}
+
+// Login lewat POST /auth/login dan mengembalikan token-nya.
+func (h *Harness) Login(email, password string) string {
+	h.T.Helper()
+	res := h.Do(http.MethodPost, "/auth/login", "", map[string]string{"email": email, "password": password})
+	if res.Code != http.StatusOK {
+		h.T.Fatalf("login %s: status %d: %s", email, res.Code, res.Raw)
+	}
+	var data struct {
+		Token string `json:"token"`
+	}
+	res.Decode(&data)
+	return data.Token
+}
+
+// LoginAs token untuk user fixture; token di-cache per user.
+func (h *Harness) LoginAs(u models.User) string {
+	h.T.Helper()
+	if tok, ok := h.tokens[u.ID]; ok {
+		return tok
+	}
+	tok := h.Login(u.Email, Password)
+	h.tokens[u.ID] = tok
+	return tok
+}
+
+func (h *Harness) AdminToken() string { return h.LoginAs(h.Admin) }
+
+func (h *Harness) UserToken() string { return h.LoginAs(h.User) }
+
+// Forget menghapus token cache u, dipakai setelah token u di-revoke.
+func (h *Harness) Forget(u models.User) { delete(h.tokens, u.ID) }
+
+// Response hasil satu request ke router.
+type Response struct {
+	T       *testing.T
+	Code    int
+	Header  http.Header
+	Raw     []byte
+	Success bool
+	Message string
+	Data    json.RawMessage
+	Error   *response.ErrorBody // nil untuk response sukses
+}
+
+// Decode mem-parse field data pada envelope ke dst.
+func (r *Response) Decode(dst interface{}) {
+	r.T.Helper()
+	if err := json.Unmarshal(r.Data, dst); err != nil {
+		r.T.Fatalf("decode data: %v: %s", err, r.Raw)
+	}
+}
+
+// Do mengirim request ke router. body berupa string/[]byte dikirim apa adanya, selain itu di-encode JSON.
+// header berpasangan key, value.
+func (h *Harness) Do(method, path, token string, body interface{}, header ...string) *Response {
+	h.T.Helper()
+	return h.do(h.T, method, path, token, body, header...)
+}
+
+func (h *Harness) do(t *testing.T, method, path, token string, body interface{}, header ...string) *Response {
+	t.Helper()
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = strings.NewReader(b)
+	case []byte:
+		r = bytes.NewReader(b)
+	default:
+		buf, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+		r = bytes.NewReader(buf)
+	}
+	req := httptest.NewRequest(method, path, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+
+	res := &Response{T: t, Code: w.Code, Header: w.Header(), Raw: w.Body.Bytes()}
+	var env struct {
+		Success bool                `json:"success"`
+		Message string              `json:"message"`
+		Data    json.RawMessage     `json:"data"`
+		Error   *response.ErrorBody `json:"error"`
+	}
+	if json.Unmarshal(res.Raw, &env) == nil {
+		res.Success, res.Message, res.Data, res.Error = env.Success, env.Message, env.Data, env.Error
+	}
+	return res
+}
+
+// Caller identitas pengirim request pada Case.
+type Caller int
+
+const (
+	Anonymous Caller = iota
+	AsUser
+	AsAdmin
+)
+
+// Case satu baris test tabel: request lalu status yang diharapkan.
+type Case struct {
+	Name   string
+	Method string
+	Path   string
+	As     Caller
+	Token  string // kalau diisi, dipakai menggantikan As
+	Body   interface{}
+	Header []string
+	Want   int
+	Code   string                          // opsional, error.code yang diharapkan
+	Check  func(t *testing.T, r *Response) // opsional, cek tambahan setelah status cocok
+}
+
+// Run menjalankan cases berurutan sebagai subtest; case boleh bergantung pada efek case sebelumnya.
+func (h *Harness) Run(cases []Case) {
+	h.T.Helper()
+	for _, tc := range cases {
+		h.T.Run(tc.Name, func(t *testing.T) {
+			token := tc.Token
+			if token == "" {
+				switch tc.As {
+				case AsUser:
+					token = h.UserToken()
+				case AsAdmin:
+					token = h.AdminToken()
+				}
+			}
+			res := h.do(t, tc.Method, tc.Path, token, tc.Body, tc.Header...)
+			if res.Code != tc.Want {
+				t.Fatalf("%s %s: status %d, want %d: %s", tc.Method, tc.Path, res.Code, tc.Want, res.Raw)
+			}
+			if tc.Code != "" && (res.Error == nil || res.Error.Code != tc.Code) {
+				t.Fatalf("%s %s: error = %+v, want code %s", tc.Method, tc.Path, res.Error, tc.Code)
+			}
+			if tc.Check != nil {
+				tc.Check(t, res)
+			}
+		})
+	}
+}
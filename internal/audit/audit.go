@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+const (
+	ActionCreate         = "create"
+	ActionUpdate         = "update"
+	ActionDelete         = "delete"
+	ActionRestore        = "restore"
+	ActionPurge          = "purge"
+	ActionSuspend        = "suspend"
+	ActionDeactivate     = "deactivate"
+	ActionReactivate     = "reactivate"
+	ActionForceLogout    = "force_logout"
+	ActionReassign       = "reassign"
+	ActionRegister       = "register"
+	ActionLogin          = "login"
+	ActionLoginFailed    = "login_failed"
+	ActionLogout         = "logout"
+	ActionForgotPassword = "forgot_password"
+	ActionResetPassword  = "reset_password"
+)
+
+// Field yang tidak ikut di-diff karena selalu berubah / tidak bermakna untuk review.
+var ignoredFields = map[string]bool{"updated_at": true}
+
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type Entry struct {
+	Action     string
+	EntityType string
+	EntityID   interface{}
+	Before     interface{} // nil untuk create
+	After      interface{} // nil untuk delete
+	Extra      map[string]interface{}
+	Actor      *models.User // default: actor dari Meta di context
+}
+
+// Diff membandingkan representasi JSON before dan after, hanya field yang berubah yang dikembalikan.
+func Diff(before, after interface{}) map[string]Change {
+	b, a := toMap(before), toMap(after)
+	out := map[string]Change{}
+	for k, v := range a {
+		if ignoredFields[k] {
+			continue
+		}
+		old, ok := b[k]
+		if !ok && v == nil {
+			continue
+		}
+		if !ok || !reflect.DeepEqual(old, v) {
+			out[k] = Change{From: old, To: v}
+		}
+	}
+	for k, v := range b {
+		if ignoredFields[k] {
+			continue
+		}
+		if _, ok := a[k]; !ok && v != nil {
+			out[k] = Change{From: v, To: nil}
+		}
+	}
+	return out
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	if v == nil {
+		return out
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+	_ = json.Unmarshal(raw, &out)
+	return out
+}
+
+// Meta identitas request yang melakukan perubahan: actor (nil sebelum login), IP dan request id.
+type Meta struct {
+	Actor     *models.User
+	IP        string
+	RequestID string
+}
+
+type metaKey struct{}
+
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+func MetaFrom(ctx context.Context) Meta {
+	m, _ := ctx.Value(metaKey{}).(Meta)
+	return m
+}
+
+// Middleware menyimpan Meta request di context supaya service bisa menulis audit di transaksi yang sama
+// dengan perubahannya. Pasang setelah AuthRequired supaya actor terisi.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m := Meta{IP: c.ClientIP(), RequestID: requestID(c)}
+		if v, ok := c.Get("user"); ok {
+			if u, ok := v.(models.User); ok {
+				m.Actor = &u
+			}
+		}
+		c.Request = c.Request.WithContext(WithMeta(c.Request.Context(), m))
+		c.Next()
+	}
+}
+
+// Row membangun baris audit_log untuk e dengan actor, IP dan request id dari Meta di ctx.
+func Row(ctx context.Context, e Entry) models.AuditLog {
+	m := MetaFrom(ctx)
+	row := models.AuditLog{
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		IP:         m.IP,
+		RequestID:  m.RequestID,
+	}
+	if e.EntityID != nil {
+		row.EntityID = fmt.Sprint(e.EntityID)
+	}
+
+	actor := e.Actor
+	if actor == nil {
+		actor = m.Actor
+	}
+	if actor != nil && actor.ID != 0 {
+		row.ActorUserID = &actor.ID
+		row.ActorEmail = actor.Email
+	}
+
+	payload := map[string]interface{}{}
+	if e.Before != nil || e.After != nil {
+		if d := Diff(e.Before, e.After); len(d) > 0 {
+			payload["diff"] = d
+		}
+	}
+	for k, v := range e.Extra {
+		payload[k] = v
+	}
+	if len(payload) > 0 {
+		if raw, err := json.Marshal(payload); err == nil {
+			row.Changes = raw
+		}
+	}
+	return row
+}
+
+func requestID(c *gin.Context) string {
+	if v := c.GetString("request_id"); v != "" {
+		return v
+	}
+	return c.GetHeader("X-Request-ID")
+}
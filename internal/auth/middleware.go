@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
 )
@@ -17,7 +19,7 @@ func AuthRequired(cfg *config.Config, db *gorm.DB) gin.HandlerFunc {
 		h := c.GetHeader("Authorization")
 		parts := strings.SplitN(h, " ", 2)
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			response.Unauthorized(c, "missing bearer token")
+			response.Fail(c, http.StatusUnauthorized, response.CodeTokenMissing, "missing bearer token", nil)
 			c.Abort()
 			return
 		}
@@ -27,28 +29,38 @@ func AuthRequired(cfg *config.Config, db *gorm.DB) gin.HandlerFunc {
 			return []byte(cfg.JWTSecret), nil
 		})
 		if err != nil || !token.Valid {
-			response.Unauthorized(c, "invalid or expired token")
+			response.Fail(c, http.StatusUnauthorized, response.CodeTokenInvalid, "invalid or expired token", nil)
 			c.Abort()
 			return
 		}
 
 		claims, ok := token.Claims.(*Claims)
 		if !ok {
-			response.Unauthorized(c, "invalid claims")
+			response.Fail(c, http.StatusUnauthorized, response.CodeTokenInvalid, "invalid claims", nil)
 			c.Abort()
 			return
 		}
 
+		// user_id ikut di semua log request ini, termasuk kalau akun ditolak di bawah
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "user_id", claims.UserID))
+
 		var user models.User
-		if err := db.First(&user, claims.UserID).Error; err != nil {
-			response.Unauthorized(c, "user not found")
+		if err := db.WithContext(c.Request.Context()).First(&user, claims.UserID).Error; err != nil {
+			response.Fail(c, http.StatusUnauthorized, response.CodeTokenInvalid, "user not found", nil)
 			c.Abort()
 			return
 		}
 
 		// Token-version check (logout invalidation)
 		if user.TokenVersion != claims.TokenVersion {
-			response.Unauthorized(c, "token revoked")
+			response.Fail(c, http.StatusUnauthorized, response.CodeTokenRevoked, "token revoked", nil)
+			c.Abort()
+			return
+		}
+
+		// Akun suspended/deactivated tidak boleh akses walau token masih valid
+		if !user.IsActive() {
+			response.Fail(c, http.StatusForbidden, response.CodeAccountInactive, "account "+user.Status, nil)
 			c.Abort()
 			return
 		}
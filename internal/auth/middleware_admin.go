@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
@@ -16,7 +18,7 @@ func AdminOnly() gin.HandlerFunc {
         }
         user := v.(models.User)
         if user.Role != "admin" {
-            response.Forbidden(c, "admin only")
+            response.Fail(c, http.StatusForbidden, response.CodeAdminOnly, "admin only", nil)
             c.Abort()
             return
         }
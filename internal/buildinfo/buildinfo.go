@@ -0,0 +1,51 @@
+// Package buildinfo informasi build yang di-embed lewat ldflags, mis.
+//
+//	go build -ldflags "-X github.com/oktaharis/uji-teknis-godigi/internal/buildinfo.Version=v1.2.0 \
+//	  -X github.com/oktaharis/uji-teknis-godigi/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/oktaharis/uji-teknis-godigi/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// Kalau tidak diisi, Commit dan BuildTime diambil dari info VCS yang dicatat `go build` (debug.ReadBuildInfo);
+// BuildTime dari VCS berisi waktu commit, bukan waktu build.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info dikembalikan GET /version.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	Modified  bool   `json:"modified,omitempty"` // working tree kotor saat build (hanya dari info VCS)
+	GoVersion string `json:"go_version"`
+}
+
+// Get informasi build binary yang sedang berjalan.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = Commit == "" && s.Value == "true"
+			}
+		}
+	}
+	return info
+}
@@ -1,37 +1,208 @@
+// Package config konfigurasi aplikasi. Urutan sumber: nilai Default, file YAML di CONFIG_FILE (opsional),
+// lalu environment variable (nama di tag `env`). Env kosong dianggap tidak diisi. Load menolak nilai yang
+// tidak bisa di-parse dan, di production, default yang tidak aman (lihat Validate).
 package config
 
-import (
-	"fmt"
-	"os"
+import "time"
+
+// Default yang tidak boleh dipakai di production.
+const (
+	DefaultJWTSecret = "supersecret_change_me"
+	DefaultDBDSN     = "root:@tcp(127.0.0.1:3306)/godigi?parseTime=true&loc=Local"
 )
 
 type Config struct {
-	AppEnv     string
-	Port       string
-	DBDSN      string
-	JWTSecret  string
-	JWTExpires int64
+	AppEnv     string `yaml:"app_env" env:"APP_ENV"`
+	Port       string `yaml:"port" env:"PORT"`
+	LogLevel   string `yaml:"log_level" env:"LOG_LEVEL"` // debug|info|warn|error
+	DBDSN      string `yaml:"db_dsn" env:"DB_DSN" secret:"dsn"`
+	JWTSecret  string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	JWTExpires int64  `yaml:"jwt_expires_in" env:"JWT_EXPIRES_IN"` // detik
+
+	// Jalankan migration yang pending saat server start
+	DBAutoMigrate bool   `yaml:"db_auto_migrate" env:"DB_AUTO_MIGRATE"`
+	DBPool        DBPool `yaml:"db_pool"`
+
+	// Replica read-only (driver harus sama dengan DB_DSN) untuk query list dan ringkasan. Kosong = semua
+	// query ke DB_DSN.
+	DBReplicaDSNs []string `yaml:"db_replica_dsns" env:"DB_REPLICA_DSNS" secret:"dsn"` // env dipisah koma
+	// Saat start, koneksi dicoba ulang sampai database bisa dihubungi atau DBConnectTimeout habis
+	// (0 = tidak dicoba ulang).
+	DBConnectTimeout time.Duration `yaml:"db_connect_timeout" env:"DB_CONNECT_TIMEOUT"`
+	DBRetry          DBRetry       `yaml:"db_retry"`
+
+	// Soft delete: data di trash dihapus permanen setelah TrashRetentionDays hari
+	TrashRetentionDays int   `yaml:"trash_retention_days" env:"TRASH_RETENTION_DAYS"`
+	TrashPurgeInterval int64 `yaml:"trash_purge_interval" env:"TRASH_PURGE_INTERVAL"` // detik, 0 = purge otomatis dimatikan
+
+	// /metrics: kalau MetricsAddr diisi (mis. ":9090") metrik hanya dilayani di port itu, bukan di router
+	// utama. MetricsToken mewajibkan `Authorization: Bearer <token>` untuk /metrics di router utama.
+	MetricsAddr  string `yaml:"metrics_addr" env:"METRICS_ADDR"`
+	MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN" secret:"true"`
+
+	// OpenTelemetry: TraceExporter none|stdout|otlp, TraceSampleRate 0..1 untuk trace baru
+	// (request dengan traceparent mengikuti keputusan sampling parent-nya).
+	TraceExporter   string  `yaml:"trace_exporter" env:"TRACE_EXPORTER"`
+	TraceSampleRate float64 `yaml:"trace_sample_rate" env:"TRACE_SAMPLE_RATE"`
+
+	// http.Server. Nilai env berupa durasi Go ("15s", "1m") atau angka detik.
+	HTTPReadHeaderTimeout time.Duration `yaml:"http_read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	HTTPReadTimeout       time.Duration `yaml:"http_read_timeout" env:"HTTP_READ_TIMEOUT"`
+	HTTPWriteTimeout      time.Duration `yaml:"http_write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout       time.Duration `yaml:"http_idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	HTTPMaxHeaderBytes    int           `yaml:"http_max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+
+	// Graceful shutdown: setelah SIGTERM readiness gagal selama ShutdownDrainDelay, lalu request yang
+	// berjalan diberi waktu ShutdownTimeout untuk selesai.
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	// HTTPS kalau keduanya diisi; file dicek berkala dan dimuat ulang kalau berubah.
+	TLSCertFile string `yaml:"tls_cert_file" env:"TLS_CERT_FILE"`
+	TLSKeyFile  string `yaml:"tls_key_file" env:"TLS_KEY_FILE"`
+
+	// Proxy/load balancer (IP atau CIDR) yang header X-Forwarded-For-nya dipercaya untuk IP client.
+	// Kosong = tidak ada, IP client diambil dari koneksi langsung.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"` // env dipisah koma
+	// Batas ukuran body request dalam byte, 0 = tanpa batas.
+	MaxBodyBytes int64 `yaml:"max_body_bytes" env:"MAX_BODY_BYTES"`
+
+	CORS      CORS      `yaml:"cors"`
+	Security  Security  `yaml:"security"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Mailer    Mailer    `yaml:"mailer"`
+	Storage   Storage   `yaml:"storage"`
+	Features  Features  `yaml:"features"`
+
+	// Admin awal yang dibuat oleh `seed admin`
+	AdminName     string `yaml:"admin_name" env:"ADMIN_NAME"`
+	AdminEmail    string `yaml:"admin_email" env:"ADMIN_EMAIL"`
+	AdminPassword string `yaml:"admin_password" env:"ADMIN_PASSWORD" secret:"true"`
 }
 
-func Load() *Config {
-	return &Config{
-		AppEnv:     get("APP_ENV", "development"),
-		Port:       get("PORT", "8080"),
-		DBDSN:      get("DB_DSN", "root:@tcp(127.0.0.1:3306)/godigi?parseTime=true&loc=Local"),
-		JWTSecret:  get("JWT_SECRET", "supersecret_change_me"),
-		JWTExpires: toInt64(get("JWT_EXPIRES_IN", "3600")),
-	}
+// DBPool pengaturan pool database/sql (tidak berlaku untuk SQLite yang selalu satu koneksi).
+// 0 berarti memakai bawaan database/sql.
+type DBPool struct {
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
 }
 
-func get(k, def string) string {
-	if v := os.Getenv(k); v != "" {
-		return v
-	}
-	return def
+// DBRetry percobaan ulang untuk error database sementara (deadlock, lock wait timeout, koneksi putus).
+// Backoff jeda sebelum percobaan kedua, berlipat dua tiap percobaan berikutnya.
+type DBRetry struct {
+	MaxAttempts int           `yaml:"max_attempts" env:"DB_RETRY_MAX_ATTEMPTS"` // termasuk percobaan pertama
+	Backoff     time.Duration `yaml:"backoff" env:"DB_RETRY_BACKOFF"`
 }
 
-func toInt64(s string) int64 {
-	var n int64 = 3600
-	_, _ = fmt.Sscan(s, &n)
-	return n
+// CORS origin browser yang boleh memanggil API. Kosong = CORS mati (hanya same-origin).
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"` // env dipisah koma
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"` // cache preflight di browser
 }
+
+// Security header keamanan di setiap response. HSTS hanya dikirim untuk request HTTPS (TLS langsung
+// atau X-Forwarded-Proto: https dari load balancer); 0 = tidak dikirim.
+type Security struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" env:"HSTS_INCLUDE_SUBDOMAINS"`
+}
+
+// RateLimit batas request per user (atau per IP kalau belum login) untuk tiap kelompok route.
+type RateLimit struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Auth    Rate `yaml:"auth" env:"RATE_LIMIT_AUTH"`     // /auth/login, /auth/register, ...
+	Read    Rate `yaml:"read" env:"RATE_LIMIT_READ"`     // GET
+	Write   Rate `yaml:"write" env:"RATE_LIMIT_WRITE"`   // POST/PUT/PATCH/DELETE
+	Export  Rate `yaml:"export" env:"RATE_LIMIT_EXPORT"` // agregat/export berat (GET /leads/summary), di atas batas read
+}
+
+// Mailer SMTP untuk email keluar. Host kosong = email tidak dikirim.
+type Mailer struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"MAIL_FROM"`
+}
+
+// Storage tempat file upload: local (folder LocalDir) atau s3 (termasuk layanan kompatibel S3 lewat Endpoint).
+type Storage struct {
+	Driver      string `yaml:"driver" env:"STORAGE_DRIVER"`
+	LocalDir    string `yaml:"local_dir" env:"STORAGE_LOCAL_DIR"`
+	S3Bucket    string `yaml:"s3_bucket" env:"STORAGE_S3_BUCKET"`
+	S3Region    string `yaml:"s3_region" env:"STORAGE_S3_REGION"`
+	S3Endpoint  string `yaml:"s3_endpoint" env:"STORAGE_S3_ENDPOINT"`
+	S3AccessKey string `yaml:"s3_access_key" env:"STORAGE_S3_ACCESS_KEY" secret:"true"`
+	S3SecretKey string `yaml:"s3_secret_key" env:"STORAGE_S3_SECRET_KEY" secret:"true"`
+}
+
+// Features fitur yang bisa dimatikan tanpa deploy ulang kode.
+type Features struct {
+	APIDocs     bool `yaml:"api_docs" env:"FEATURE_API_DOCS"`         // GET /docs dan /openapi.json
+	DebugRoutes bool `yaml:"debug_routes" env:"FEATURE_DEBUG_ROUTES"` // GET /debug/whoami
+	// Token reset password ikut di response forgot-password dan reset-password admin (belum ada mailer).
+	// Tidak pernah berlaku di production, lihat ExposeResetToken.
+	ResetTokenInResponse bool `yaml:"reset_token_in_response" env:"FEATURE_RESET_TOKEN_IN_RESPONSE"`
+}
+
+// Default konfigurasi sebelum file dan env diterapkan.
+func Default() *Config {
+	return &Config{
+		AppEnv:     "development",
+		Port:       "8080",
+		LogLevel:   "info",
+		DBDSN:      DefaultDBDSN,
+		JWTSecret:  DefaultJWTSecret,
+		JWTExpires: 3600,
+
+		DBAutoMigrate: true,
+		DBPool: DBPool{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		DBConnectTimeout: time.Minute,
+		DBRetry:          DBRetry{MaxAttempts: 3, Backoff: 50 * time.Millisecond},
+
+		TrashRetentionDays: 30,
+		TrashPurgeInterval: 86400,
+
+		TraceExporter:   "none",
+		TraceSampleRate: 1,
+
+		HTTPReadHeaderTimeout: 5 * time.Second,
+		HTTPReadTimeout:       15 * time.Second,
+		HTTPWriteTimeout:      30 * time.Second,
+		HTTPIdleTimeout:       60 * time.Second,
+		HTTPMaxHeaderBytes:    1 << 20,
+
+		ShutdownTimeout: 30 * time.Second,
+
+		MaxBodyBytes: 1 << 20,
+
+		CORS:     CORS{MaxAge: 10 * time.Minute},
+		Security: Security{HSTSMaxAge: 365 * 24 * time.Hour},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Auth:    Rate{Requests: 10, Per: time.Minute},
+			Read:    Rate{Requests: 300, Per: time.Minute},
+			Write:   Rate{Requests: 60, Per: time.Minute},
+			Export:  Rate{Requests: 5, Per: time.Minute},
+		},
+		Mailer:   Mailer{Port: 587},
+		Storage:  Storage{Driver: "local", LocalDir: "storage"},
+		Features: Features{APIDocs: true, DebugRoutes: true, ResetTokenInResponse: true},
+
+		AdminName: "Administrator",
+	}
+}
+
+// IsProduction true kalau APP_ENV=production.
+func (c *Config) IsProduction() bool { return c.AppEnv == "production" }
+
+// ExposeResetToken true kalau token reset password boleh dikembalikan di response (test mode).
+func (c *Config) ExposeResetToken() bool { return c.Features.ResetTokenInResponse && !c.IsProduction() }
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := vars[k]
+		return v, ok
+	}
+}
+
+func TestReadFileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "godigi.yaml")
+	err := os.WriteFile(path, []byte(`
+app_env: staging
+port: "9000"
+jwt_expires_in: 7200
+db_pool:
+  max_open_conns: 50
+  conn_max_lifetime: 1h
+cors:
+  allowed_origins: [https://app.godigi.id]
+rate_limit:
+  auth: 5/m
+features:
+  debug_routes: false
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Read(env(map[string]string{
+		FileEnv:                path,
+		"PORT":                 "9100",
+		"JWT_SECRET":           "", // kosong = tidak diisi
+		"DB_MAX_IDLE_CONNS":    "10",
+		"CORS_ALLOWED_ORIGINS": "https://a.test, https://b.test",
+		"RATE_LIMIT_WRITE":     "100/30s",
+		"SHUTDOWN_TIMEOUT":     "45",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct{ got, want interface{} }{
+		"app_env from file":       {cfg.AppEnv, "staging"},
+		"port env over file":      {cfg.Port, "9100"},
+		"jwt_expires from file":   {cfg.JWTExpires, int64(7200)},
+		"empty env keeps default": {cfg.JWTSecret, DefaultJWTSecret},
+		"nested from file":        {cfg.DBPool.MaxOpenConns, 50},
+		"duration from file":      {cfg.DBPool.ConnMaxLifetime, time.Hour},
+		"nested from env":         {cfg.DBPool.MaxIdleConns, 10},
+		"default kept":            {cfg.DBPool.ConnMaxIdleTime, 5 * time.Minute},
+		"list from env":           {strings.Join(cfg.CORS.AllowedOrigins, "|"), "https://a.test|https://b.test"},
+		"rate from file":          {cfg.RateLimit.Auth, Rate{Requests: 5, Per: time.Minute}},
+		"rate from env":           {cfg.RateLimit.Write, Rate{Requests: 100, Per: 30 * time.Second}},
+		"seconds as duration":     {cfg.ShutdownTimeout, 45 * time.Second},
+		"bool from file":          {cfg.Features.DebugRoutes, false},
+		"bool default":            {cfg.Features.APIDocs, true},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestReadRejectsBadValues(t *testing.T) {
+	_, err := Read(env(map[string]string{
+		"JWT_EXPIRES_IN":    "1h", // dulu diam-diam jadi 3600
+		"DB_AUTO_MIGRATE":   "yes please",
+		"RATE_LIMIT_READ":   "lots",
+		"HTTP_READ_TIMEOUT": "soon",
+	}))
+	if err == nil {
+		t.Fatal("want error")
+	}
+	for _, want := range []string{"JWT_EXPIRES_IN", "DB_AUTO_MIGRATE", "RATE_LIMIT_READ", "HTTP_READ_TIMEOUT"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s: %v", want, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "godigi.yaml")
+	_ = os.WriteFile(path, []byte("jwt_secert: typo\n"), 0o600)
+	if _, err := Read(env(map[string]string{FileEnv: path})); err == nil || !strings.Contains(err.Error(), "jwt_secert") {
+		t.Fatalf("unknown key: err = %v", err)
+	}
+	if _, err := Read(env(map[string]string{FileEnv: "godigi.toml"})); err == nil {
+		t.Fatal("want error for unsupported file format")
+	}
+}
+
+func TestValidateProductionDefaults(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("development defaults: %v", err)
+	}
+
+	cfg := Default()
+	cfg.AppEnv = "production"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("production with default secrets must fail")
+	}
+	for _, want := range []string{"JWT_SECRET", "DB_DSN", "METRICS_TOKEN"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s: %v", want, err)
+		}
+	}
+
+	cfg.JWTSecret = strings.Repeat("k", 32)
+	cfg.DBDSN = "godigi:s3cret@tcp(db:3306)/godigi?parseTime=true"
+	cfg.MetricsAddr = ":9090"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("hardened production config: %v", err)
+	}
+
+	cfg.CORS = CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	cfg.TraceSampleRate = 2
+	cfg.Storage.Driver = "s3"
+	cfg.TrustedProxies = []string{"10.0.0.0/8", "load-balancer"}
+	err = cfg.Validate()
+	for _, want := range []string{"CORS_ALLOWED_ORIGINS", "TRACE_SAMPLE_RATE", "STORAGE_S3_BUCKET", `"load-balancer"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s: %v", want, err)
+		}
+	}
+}
+
+func TestRateText(t *testing.T) {
+	for in, want := range map[string]string{"10/m": "10/m", "100/30s": "100/30s", "5/1h": "5/h", "": ""} {
+		var r Rate
+		if err := r.UnmarshalText([]byte(in)); err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if r.String() != want {
+			t.Errorf("%q round trip = %q, want %q", in, r.String(), want)
+		}
+	}
+	for _, in := range []string{"10", "x/m", "10/fortnight", "-1/m"} {
+		var r Rate
+		if err := r.UnmarshalText([]byte(in)); err == nil {
+			t.Errorf("%q: want error", in)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.DBDSN = "godigi:s3cret@tcp(db:3306)/godigi?parseTime=true"
+	cfg.MetricsToken = "scrape-token"
+	cfg.Mailer.Password = "smtp-pass"
+	cfg.DBReplicaDSNs = []string{"godigi:s3cret@tcp(replica:3306)/godigi"}
+	out := cfg.Redacted()
+
+	if out.JWTSecret != redacted || out.MetricsToken != redacted || out.Mailer.Password != redacted {
+		t.Fatalf("secrets not redacted: %+v", out)
+	}
+	if out.AdminPassword != "" {
+		t.Fatalf("empty secret should stay empty, got %q", out.AdminPassword)
+	}
+	if cfg.JWTSecret != DefaultJWTSecret {
+		t.Fatal("Redacted modified the original config")
+	}
+	if want := "godigi:[REDACTED]@tcp(db:3306)/godigi?parseTime=true"; out.DBDSN != want {
+		t.Fatalf("DSN = %s, want %s", out.DBDSN, want)
+	}
+	if want := "godigi:[REDACTED]@tcp(replica:3306)/godigi"; out.DBReplicaDSNs[0] != want {
+		t.Fatalf("replica DSN = %s, want %s", out.DBReplicaDSNs[0], want)
+	}
+	if cfg.DBReplicaDSNs[0] != "godigi:s3cret@tcp(replica:3306)/godigi" {
+		t.Fatal("Redacted modified the original replica DSNs")
+	}
+
+	for in, want := range map[string]string{
+		"postgres://app:pw@db:5432/godigi?sslmode=disable": "postgres://app:[REDACTED]@db:5432/godigi?sslmode=disable",
+		"host=db user=app password=pw dbname=godigi":       "host=db user=app password=[REDACTED] dbname=godigi",
+		"root:@tcp(127.0.0.1:3306)/godigi":                 "root:@tcp(127.0.0.1:3306)/godigi",
+		"sqlite:godigi.db":                                 "sqlite:godigi.db",
+	} {
+		if got := RedactDSN(in); got != want {
+			t.Errorf("RedactDSN(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv env berisi path file konfigurasi YAML.
+const FileEnv = "CONFIG_FILE"
+
+// Load membaca konfigurasi (lihat Read) lalu memvalidasinya.
+func Load() (*Config, error) {
+	cfg, err := Read(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Read menerapkan Default, file CONFIG_FILE dan env tanpa validasi. lookup biasanya os.LookupEnv.
+func Read(lookup func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	if path, ok := lookup(FileEnv); ok && path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), lookup); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile menimpa nilai cfg dengan isi file YAML; key yang tidak dikenal ditolak supaya salah ketik
+// tidak diam-diam diabaikan.
+func (c *Config) readFile(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config: %s: unsupported format, want .yaml or .yml", path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// applyEnv mengisi field bertag `env` (rekursif ke struct bertingkat) dari lookup. Semua nilai yang gagal
+// di-parse dilaporkan sekaligus.
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		name := f.Tag.Get("env")
+		if name == "" {
+			if f.Type.Kind() == reflect.Struct && f.Type != durationType {
+				errs = append(errs, applyEnv(fv, lookup))
+			}
+			continue
+		}
+		s, ok := lookup(name)
+		if !ok || strings.TrimSpace(s) == "" {
+			continue
+		}
+		if err := setValue(fv, strings.TrimSpace(s)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := parseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, it := range strings.Split(s, ",") {
+			if it = strings.TrimSpace(it); it != "" {
+				items = append(items, it)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
+
+// parseDuration durasi Go ("15s", "1m") atau angka detik ("15").
+func parseDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+	return 0, fmt.Errorf("%q is not a duration (e.g. 15s, 1m) or a number of seconds", s)
+}
+
+// Rate batas rate limit dalam bentuk "N/periode", mis. "10/m" atau "100/30s": bucket berisi N token yang
+// terisi penuh lagi setiap periode. Kosong berarti tanpa batas.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// IsZero true kalau rate tidak diisi (tanpa batas).
+func (r Rate) IsZero() bool { return r.Requests <= 0 || r.Per <= 0 }
+
+var ratePeriods = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+func (r *Rate) UnmarshalText(b []byte) error {
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		*r = Rate{}
+		return nil
+	}
+	n, per, ok := strings.Cut(s, "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(n))
+	if !ok || err != nil || requests < 0 {
+		return fmt.Errorf("invalid rate %q, want N/period such as 10/m or 100/30s", s)
+	}
+	per = strings.TrimSpace(per)
+	d, known := ratePeriods[per]
+	if !known {
+		if d, err = time.ParseDuration(per); err != nil || d <= 0 {
+			return fmt.Errorf("invalid rate period %q in %q", per, s)
+		}
+	}
+	*r = Rate{Requests: requests, Per: d}
+	return nil
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	if r.IsZero() {
+		return []byte{}, nil
+	}
+	for unit, d := range ratePeriods {
+		if r.Per == d {
+			return []byte(fmt.Sprintf("%d/%s", r.Requests, unit)), nil
+		}
+	}
+	return []byte(fmt.Sprintf("%d/%s", r.Requests, r.Per)), nil
+}
+
+func (r Rate) String() string {
+	b, _ := r.MarshalText()
+	return string(b)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"slices"
+)
+
+// Validate memeriksa nilai yang tidak masuk akal di semua environment, dan di production juga menolak
+// default yang tidak aman. Semua masalah dilaporkan sekaligus.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Port != "", "PORT must not be empty")
+	check(slices.Contains([]string{"debug", "info", "warn", "error"}, c.LogLevel),
+		"LOG_LEVEL must be one of debug, info, warn, error (got %q)", c.LogLevel)
+	check(c.JWTExpires > 0, "JWT_EXPIRES_IN must be a positive number of seconds (got %d)", c.JWTExpires)
+	check(c.TrashRetentionDays >= 0, "TRASH_RETENTION_DAYS must not be negative")
+	check(c.TrashPurgeInterval >= 0, "TRASH_PURGE_INTERVAL must not be negative")
+	check(slices.Contains([]string{"", "none", "stdout", "otlp"}, c.TraceExporter),
+		"TRACE_EXPORTER must be one of none, stdout, otlp (got %q)", c.TraceExporter)
+	check(c.TraceSampleRate >= 0 && c.TraceSampleRate <= 1, "TRACE_SAMPLE_RATE must be between 0 and 1")
+	check((c.TLSCertFile == "") == (c.TLSKeyFile == ""), "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+
+	p := c.DBPool
+	check(p.MaxOpenConns >= 0 && p.MaxIdleConns >= 0 && p.ConnMaxLifetime >= 0 && p.ConnMaxIdleTime >= 0,
+		"db_pool values must not be negative")
+	check(p.MaxOpenConns == 0 || p.MaxIdleConns <= p.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", p.MaxIdleConns, p.MaxOpenConns)
+	check(c.DBConnectTimeout >= 0, "DB_CONNECT_TIMEOUT must not be negative")
+	check(c.DBRetry.MaxAttempts >= 1, "DB_RETRY_MAX_ATTEMPTS must be at least 1 (got %d)", c.DBRetry.MaxAttempts)
+	check(c.DBRetry.Backoff >= 0, "DB_RETRY_BACKOFF must not be negative")
+
+	for _, proxy := range c.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES: %q is not an IP address or CIDR", proxy)
+	}
+	check(c.MaxBodyBytes >= 0, "MAX_BODY_BYTES must not be negative")
+	check(c.Security.HSTSMaxAge >= 0, "HSTS_MAX_AGE must not be negative")
+	check(c.CORS.MaxAge >= 0, "CORS_MAX_AGE must not be negative")
+	for _, o := range c.CORS.AllowedOrigins {
+		u, err := url.Parse(o)
+		check(o == "*" || err == nil && u.Scheme != "" && u.Host != "" && (u.Path == "" || u.Path == "/"),
+			"CORS_ALLOWED_ORIGINS: %q must be * or an origin such as https://app.example.com", o)
+	}
+	// Browser menolak Access-Control-Allow-Origin: * untuk request dengan credentials.
+	check(!(c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*")),
+		"CORS_ALLOWED_ORIGINS must list explicit origins when CORS_ALLOW_CREDENTIALS is true")
+
+	if c.Mailer.Host != "" {
+		_, err := mail.ParseAddress(c.Mailer.From)
+		check(err == nil, "MAIL_FROM must be a valid address when SMTP_HOST is set")
+		check(c.Mailer.Port > 0, "SMTP_PORT must be positive")
+	}
+	switch c.Storage.Driver {
+	case "local":
+		check(c.Storage.LocalDir != "", "STORAGE_LOCAL_DIR must be set for the local storage driver")
+	case "s3":
+		check(c.Storage.S3Bucket != "", "STORAGE_S3_BUCKET must be set for the s3 storage driver")
+	default:
+		check(false, "STORAGE_DRIVER must be local or s3 (got %q)", c.Storage.Driver)
+	}
+
+	if c.IsProduction() {
+		check(c.JWTSecret != DefaultJWTSecret && len(c.JWTSecret) >= 32,
+			"JWT_SECRET must be a random value of at least 32 characters in production")
+		check(c.DBDSN != DefaultDBDSN, "DB_DSN must not use the default passwordless root account in production")
+		check(c.MetricsToken != "" || c.MetricsAddr != "",
+			"set METRICS_TOKEN or METRICS_ADDR in production, otherwise /metrics is public")
+		check(c.AdminPassword == "" || len(c.AdminPassword) >= 12,
+			"ADMIN_PASSWORD must be at least 12 characters in production")
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+	return nil
+}
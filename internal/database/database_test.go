@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	goMysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+var deadlock = &goMysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+func testConfig(dsn string) *config.Config {
+	cfg := config.Default()
+	cfg.DBDSN = dsn
+	cfg.DBRetry = config.DBRetry{MaxAttempts: 3, Backoff: time.Millisecond}
+	cfg.DBConnectTimeout = 0
+	return cfg
+}
+
+func connect(t *testing.T, cfg *config.Config) *gorm.DB {
+	t.Helper()
+	db, err := Connect(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, tc := range []struct {
+		err                 error
+		conflict, transient bool
+	}{
+		{deadlock, true, true},
+		{&goMysql.MySQLError{Number: 1205}, true, true},
+		{fmt.Errorf("update: %w", &pgconn.PgError{Code: "40001"}), true, true},
+		{errors.New("database is locked (5) (SQLITE_BUSY)"), true, true},
+		{driver.ErrBadConn, false, true},
+		{goMysql.ErrInvalidConn, false, true},
+		{&goMysql.MySQLError{Number: 1062}, false, false},
+		{gorm.ErrRecordNotFound, false, false},
+		{nil, false, false},
+	} {
+		if got := IsConflict(tc.err); got != tc.conflict {
+			t.Errorf("IsConflict(%v) = %v, want %v", tc.err, got, tc.conflict)
+		}
+		if got := IsTransient(tc.err); got != tc.transient {
+			t.Errorf("IsTransient(%v) = %v, want %v", tc.err, got, tc.transient)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	p := config.DBRetry{MaxAttempts: 3, Backoff: time.Millisecond}
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, p, IsTransient, func() error {
+		if calls++; calls < 3 {
+			return deadlock
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("err = %v after %d calls, want success on the 3rd", err, calls)
+	}
+
+	calls = 0
+	err = Retry(ctx, p, IsTransient, func() error { calls++; return deadlock })
+	if !errors.Is(err, deadlock) || calls != 3 {
+		t.Fatalf("err = %v after %d calls, want deadlock after MaxAttempts", err, calls)
+	}
+
+	calls = 0
+	notFound := gorm.ErrRecordNotFound
+	err = Retry(ctx, p, IsTransient, func() error { calls++; return notFound })
+	if !errors.Is(err, notFound) || calls != 1 {
+		t.Fatalf("err = %v after %d calls, want non-transient error returned at once", err, calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 6: time.Second} {
+		got := backoff(100*time.Millisecond, attempt, time.Second)
+		if got < want/2 || got > want {
+			t.Errorf("backoff(attempt %d) = %s, want within [%s, %s]", attempt, got, want/2, want)
+		}
+	}
+}
+
+// Query yang gagal karena deadlock diulang oleh retryPlugin; di dalam transaksi tidak.
+func TestRetryPluginRetriesQueries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "retry.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := 0
+	query := db.Callback().Query().Get("gorm:query")
+	_ = db.Callback().Query().Replace("gorm:query", func(db *gorm.DB) {
+		if failures > 0 {
+			failures--
+			db.AddError(deadlock)
+			return
+		}
+		query(db)
+	})
+	if err := db.Use(&retryPlugin{policy: config.DBRetry{MaxAttempts: 3, Backoff: time.Millisecond}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int64
+	failures = 2
+	if err := db.Raw("SELECT 1").Find(&n).Error; err != nil || n != 1 || failures != 0 {
+		t.Fatalf("query after 2 deadlocks: n=%d err=%v, want retried to success", n, err)
+	}
+
+	failures = 1
+	err = db.Transaction(func(tx *gorm.DB) error { return tx.Raw("SELECT 1").Find(&n).Error })
+	if !errors.Is(err, deadlock) {
+		t.Fatalf("query in tx: err = %v, want deadlock returned without retry", err)
+	}
+}
+
+func TestTransactionRetriesConflicts(t *testing.T) {
+	db := connect(t, testConfig("sqlite:"+filepath.Join(t.TempDir(), "tx.db")))
+	ctx := context.Background()
+
+	calls := 0
+	err := Transaction(ctx, db, func(tx *gorm.DB) error {
+		if calls++; calls == 1 {
+			return deadlock
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("err = %v after %d calls, want the transaction retried once", err, calls)
+	}
+
+	calls = 0
+	err = Transaction(ctx, db, func(tx *gorm.DB) error { calls++; return driver.ErrBadConn })
+	if !errors.Is(err, driver.ErrBadConn) || calls != 1 {
+		t.Fatalf("err = %v after %d calls, want connection errors not retried inside a transaction", err, calls)
+	}
+}
+
+// ReadReplica membaca dari replica, query lain tetap ke primary.
+func TestReadReplica(t *testing.T) {
+	dir := t.TempDir()
+	primary, replica := filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db")
+	for path, name := range map[string]string{primary: "primary", replica: "replica"} {
+		db := connect(t, testConfig("sqlite:"+path))
+		if err := db.Exec("CREATE TABLE notes (name TEXT)").Error; err != nil {
+			t.Fatal(err)
+		}
+		db.Exec("INSERT INTO notes (name) VALUES (?)", name)
+	}
+
+	cfg := testConfig("sqlite:" + primary)
+	cfg.DBReplicaDSNs = []string{"sqlite:" + replica}
+	db := connect(t, cfg)
+
+	var got []string
+	db.Table("notes").Pluck("name", &got)
+	if len(got) != 1 || got[0] != "primary" {
+		t.Fatalf("default read = %v, want primary", got)
+	}
+	ReadReplica(db).Table("notes").Pluck("name", &got)
+	if len(got) != 1 || got[0] != "replica" {
+		t.Fatalf("ReadReplica read = %v, want replica", got)
+	}
+
+	cfg.DBReplicaDSNs = []string{"postgres://replica/godigi"}
+	if _, err := Connect(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("mismatched replica driver: err = %v", err)
+	}
+}
+
+// Connect menunggu database yang belum bisa dibuka, bukan langsung gagal.
+func TestConnectRetriesUntilReachable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "later")
+	cfg := testConfig("sqlite:" + filepath.Join(dir, "app.db"))
+
+	if _, err := Connect(context.Background(), cfg); err == nil {
+		t.Fatal("Connect without DB_CONNECT_TIMEOUT should fail at once")
+	}
+
+	cfg.DBConnectTimeout = 10 * time.Second
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		os.MkdirAll(dir, 0o755)
+	}()
+	connect(t, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg.DBDSN = "sqlite:" + filepath.Join(t.TempDir(), "missing", "app.db")
+	if _, err := Connect(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Connect with canceled ctx: err = %v, want context.Canceled", err)
+	}
+}
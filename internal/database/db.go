@@ -1,39 +1,141 @@
 package database
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 
 	"github.com/oktaharis/uji-teknis-godigi/internal/config"
-	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
 )
 
-func Connect(cfg *config.Config) *gorm.DB {
-	db, err := gorm.Open(mysql.Open(cfg.DBDSN), &gorm.Config{
+// Replica nama resolver dbresolver untuk query yang boleh dibaca dari replica, lihat ReadReplica.
+const Replica = "replica"
+
+// connectBackoff jeda awal dan maksimal antar percobaan koneksi saat start.
+const (
+	connectBackoff    = 500 * time.Millisecond
+	maxConnectBackoff = 10 * time.Second
+)
+
+// Connect hanya membuka koneksi; skema dikelola lewat package migrate (lihat `migrate up`).
+// Driver (MySQL, PostgreSQL, SQLite) dipilih dari bentuk DB_DSN, lihat Dialect. Kalau database belum bisa
+// dihubungi, Connect mencoba lagi dengan backoff sampai cfg.DBConnectTimeout habis atau ctx selesai.
+func Connect(ctx context.Context, cfg *config.Config) (*gorm.DB, error) {
+	deadline := time.Now().Add(cfg.DBConnectTimeout)
+	for attempt := 1; ; attempt++ {
+		db, err := open(cfg)
+		if err == nil {
+			if attempt > 1 {
+				slog.Info("db connected", "attempts", attempt)
+			}
+			return db, nil
+		}
+		wait := backoff(connectBackoff, attempt, maxConnectBackoff)
+		if time.Now().Add(wait).After(deadline) {
+			return nil, fmt.Errorf("db connect: %w (gave up after %d attempts)", err, attempt)
+		}
+		slog.Warn("db not reachable, retrying", "attempt", attempt, "retry_in", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("db connect: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// open satu percobaan koneksi: primary, replica (kalau ada) dan pool. gorm.Open dan resolver.Call mem-ping
+// semua koneksi, jadi database yang belum siap ketahuan di sini, bukan di request pertama.
+func open(cfg *config.Config) (db *gorm.DB, err error) {
+	db, err = gorm.Open(dialector(cfg.DBDSN), &gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true,
 		SkipDefaultTransaction:                   true,
 		PrepareStmt:                              true,
+		Logger:                                   logging.NewGormLogger(logging.ParseLevel(cfg.LogLevel)),
 	})
 	if err != nil {
-		log.Fatalf("db connect error: %v", err)
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close() // gorm.Open tidak menutup koneksi kalau ping gagal
+			}
+		}
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			sqlDB.Close()
+		}
+	}()
+	if err = db.Use(&retryPlugin{policy: cfg.DBRetry}); err != nil {
+		return nil, err
 	}
 
-	// Pastikan tabel perusahaan ada (tanpa ALTER)
-	if err := EnsureCompanyTables(db); err != nil {
-		log.Fatalf("bootstrap company tables error: %v", err)
+	dialect := Dialect(cfg.DBDSN)
+	configure := func(sqlDB *sql.DB) {
+		if dialect == SQLite {
+			// SQLite hanya satu writer; satu koneksi juga membuat :memory: tetap satu database
+			sqlDB.SetMaxOpenConns(1)
+		} else {
+			ConfigurePool(sqlDB, cfg.DBPool)
+		}
 	}
+	configure(sqlDB)
 
-	// Migrasi tabel internal saja
-	if err := db.Set("gorm:table_options", "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4").
-		AutoMigrate(
-			&models.User{},
-			&models.PasswordReset{},
-			&models.Project{},
-		); err != nil {
-		log.Fatalf("auto-migrate error: %v", err)
+	if len(cfg.DBReplicaDSNs) > 0 {
+		replicas := make([]gorm.Dialector, len(cfg.DBReplicaDSNs))
+		for i, dsn := range cfg.DBReplicaDSNs {
+			if Dialect(dsn) != dialect {
+				return nil, fmt.Errorf("replica %d: driver %s does not match DB_DSN (%s)", i+1, Dialect(dsn), dialect)
+			}
+			replicas[i] = dialector(dsn)
+		}
+		// Resolver bernama, bukan global: query lain tetap ke primary kecuali memakai ReadReplica.
+		resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas, Policy: dbresolver.RandomPolicy{}}, Replica)
+		if err = db.Use(resolver); err != nil {
+			return nil, err
+		}
+		err = resolver.Call(func(pool gorm.ConnPool) error {
+			if rdb, ok := pool.(*sql.DB); ok {
+				configure(rdb)
+				return rdb.Ping()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
+	return db, nil
+}
+
+// ReadReplica mengarahkan query db ke replica kalau DB_REPLICA_DSNS diisi. Hanya untuk bacaan yang boleh
+// sedikit tertinggal (list, ringkasan); di dalam transaksi query tetap memakai koneksi transaksi.
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(Replica))
+}
 
-	return db
+// ConfigurePool menerapkan batas koneksi dan umur koneksi dari config; nilai 0 dilewati sehingga
+// bawaan database/sql yang berlaku.
+func ConfigurePool(sqlDB *sql.DB, p config.DBPool) {
+	if p.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
 }
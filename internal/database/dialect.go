@@ -0,0 +1,80 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	goMysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// Dialect menebak driver dari bentuk DB_DSN:
+//
+//	sqlite:godigi.db, sqlite::memory:, file:godigi.db   -> sqlite
+//	postgres://..., postgresql://..., host=... dbname=... -> postgres
+//	selain itu (format go-sql-driver)                    -> mysql
+func Dialect(dsn string) string {
+	switch {
+	case strings.HasPrefix(dsn, "sqlite:"), strings.HasPrefix(dsn, "file:"):
+		return SQLite
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"),
+		strings.HasPrefix(dsn, "host=") && strings.Contains(dsn, "dbname="):
+		return Postgres
+	default:
+		return MySQL
+	}
+}
+
+func dialector(dsn string) gorm.Dialector {
+	switch Dialect(dsn) {
+	case SQLite:
+		return sqlite.Open(sqliteDSN(dsn))
+	case Postgres:
+		return postgres.Open(dsn)
+	default:
+		return mysql.Open(dsn)
+	}
+}
+
+// sqliteDSN membuang prefix "sqlite:" dan menyalakan foreign key (default SQLite mati),
+// supaya ON DELETE CASCADE di deals tetap berlaku.
+func sqliteDSN(dsn string) string {
+	dsn = strings.TrimPrefix(dsn, "sqlite:")
+	if strings.Contains(dsn, "foreign_keys") {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+// IsUniqueViolation true kalau err berasal dari pelanggaran unique constraint di dialect mana pun.
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var me *goMysql.MySQLError
+	if errors.As(err, &me) {
+		return me.Number == 1062
+	}
+	var pe *pgconn.PgError
+	if errors.As(err, &pe) {
+		return pe.Code == "23505"
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
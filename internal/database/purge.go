@@ -0,0 +1,53 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+// PurgeTrash menghapus permanen semua baris soft-deleted yang deleted_at-nya sebelum `before`.
+// Hasilnya jumlah baris terhapus per tabel.
+func PurgeTrash(db *gorm.DB, before time.Time) (map[string]int64, error) {
+	out := map[string]int64{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// deals dulu supaya tidak bergantung pada ON DELETE CASCADE
+		res := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.Deal{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out["deals"] = res.RowsAffected
+
+		res = tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.Lead{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out["leads"] = res.RowsAffected
+
+		res = tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.Project{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out["projects"] = res.RowsAffected
+
+		// User yang dipurge: lepas kepemilikan project dan buang token reset-nya
+		purged := tx.Unscoped().Model(&models.User{}).Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		if err := tx.Unscoped().Model(&models.Project{}).Where("owner_user_id IN (?)", purged).
+			Update("owner_user_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", purged).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		res = tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.User{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out["users"] = res.RowsAffected
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"syscall"
+	"time"
+
+	goMysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+// maxRetryBackoff batas jeda antar percobaan supaya request tidak tertahan terlalu lama.
+const maxRetryBackoff = 2 * time.Second
+
+// IsConflict true kalau err akibat bentrok dengan transaksi lain (deadlock, lock wait timeout,
+// serialization failure). Statement ditolak utuh sehingga aman diulang, termasuk untuk tulis.
+func IsConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	var me *goMysql.MySQLError
+	if errors.As(err, &me) {
+		return me.Number == 1213 || me.Number == 1205 // ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+	}
+	var pe *pgconn.PgError
+	if errors.As(err, &pe) {
+		return pe.Code == "40001" || pe.Code == "40P01" || pe.Code == "55P03"
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
+
+// IsConnError true kalau koneksi ke database putus atau ditolak. Untuk tulis tidak diketahui apakah
+// statement sempat dijalankan, jadi hanya query baca yang diulang.
+func IsConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, goMysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		pgconn.SafeToRetry(err)
+}
+
+// IsTransient true kalau err kemungkinan hilang sendiri kalau query diulang.
+func IsTransient(err error) bool { return IsConflict(err) || IsConnError(err) }
+
+// Retry menjalankan fn sampai berhasil, error-nya tidak retryable, ctx selesai, atau p.MaxAttempts
+// tercapai. Jeda berlipat dua mulai p.Backoff (maksimal maxRetryBackoff) dengan jitter.
+func Retry(ctx context.Context, p config.DBRetry, retryable func(error) bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		wait := backoff(p.Backoff, attempt, maxRetryBackoff)
+		slog.WarnContext(ctx, "db transient error, retrying", "attempt", attempt, "retry_in", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff jeda setelah percobaan ke-attempt: base*2^(attempt-1) dibatasi max, diacak di rentang [d/2, d]
+// supaya request yang bentrok tidak mencoba ulang bersamaan.
+func backoff(base time.Duration, attempt int, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	d = min(d, max)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+const retryPluginName = "godigi:retry"
+
+// retryPlugin mengulang statement yang gagal karena error sementara, di luar transaksi: query baca untuk
+// semua IsTransient, tulis hanya untuk IsConflict. Di dalam transaksi error dikembalikan apa adanya;
+// transaksi diulang utuh oleh Transaction.
+type retryPlugin struct {
+	policy config.DBRetry
+}
+
+func (p *retryPlugin) Name() string { return retryPluginName }
+
+func (p *retryPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, w := range []struct {
+		name      string
+		processor interface {
+			Get(string) func(*gorm.DB)
+			Replace(string, func(*gorm.DB)) error
+		}
+		retryable func(error) bool
+	}{
+		{"gorm:query", cb.Query(), IsTransient},
+		{"gorm:row", cb.Row(), IsTransient},
+		{"gorm:create", cb.Create(), IsConflict},
+		{"gorm:update", cb.Update(), IsConflict},
+		{"gorm:delete", cb.Delete(), IsConflict},
+	} {
+		if fn := w.processor.Get(w.name); fn != nil {
+			if err := w.processor.Replace(w.name, p.wrap(fn, w.retryable)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *retryPlugin) wrap(fn func(*gorm.DB), retryable func(error) bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx || p.policy.MaxAttempts <= 1 {
+			fn(db)
+			return
+		}
+		retrying := false
+		_ = Retry(db.Statement.Context, p.policy, retryable, func() error {
+			if retrying {
+				db.Error, db.RowsAffected = nil, 0
+			}
+			retrying = true
+			fn(db)
+			return db.Error
+		})
+	}
+}
+
+// Transaction menjalankan fn dalam transaksi dan mengulang seluruh transaksi kalau gagal karena
+// IsConflict. fn bisa dipanggil lebih dari sekali, jadi tidak boleh punya efek samping di luar tx.
+// Transaksi bertingkat (savepoint) tidak diulang sendiri; yang mengulang transaksi terluar.
+func Transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	run := func() error { return db.WithContext(ctx).Transaction(fn) }
+	p, ok := db.Config.Plugins[retryPluginName].(*retryPlugin)
+	if _, nested := db.Statement.ConnPool.(gorm.TxCommitter); !ok || nested {
+		return run()
+	}
+	return Retry(ctx, p.policy, IsConflict, run)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+type AuditHandler struct {
+	Audit *service.AuditService
+}
+
+func NewAuditHandler(svc *service.AuditService) *AuditHandler { return &AuditHandler{Audit: svc} }
+
+// GET /admin/audit?entity_type=lead&entity_id=5&actor_user_id=1&action=update&request_id=...&from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *AuditHandler) List(c *gin.Context) {
+	query, p, ok := listFrom(c, repository.AuditFields, 20)
+	if !ok {
+		return
+	}
+	f := repository.AuditFilter{Query: query}
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, response.CodeInvalidQuery, "Invalid from date, expected YYYY-MM-DD", nil)
+			return
+		}
+		f.Created.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, response.CodeInvalidQuery, "Invalid to date, expected YYYY-MM-DD", nil)
+			return
+		}
+		end := t.Add(24 * time.Hour)
+		f.Created.To = &end
+	}
+	items, info, err := h.Audit.List(c.Request.Context(), f, p)
+	if err != nil {
+		respondError(c, err, "Failed to list audit log")
+		return
+	}
+	response.OK(c, cursorResult(items, p, info), "Audit log")
+}
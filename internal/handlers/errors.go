@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+// Pemetaan error service ke status dan pesan HTTP.
+var errorResponses = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	{service.ErrLeadNotFound, http.StatusNotFound, response.CodeLeadNotFound, "Lead not found"},
+	{service.ErrLeadNotInTrash, http.StatusNotFound, response.CodeLeadNotInTrash, "Lead not found in trash"},
+	{service.ErrProjectNotFound, http.StatusNotFound, response.CodeProjectNotFound, "Project not found"},
+	{service.ErrProjectNotInTrash, http.StatusNotFound, response.CodeProjectNotInTrash, "Project not found in trash"},
+	{service.ErrUserNotFound, http.StatusNotFound, response.CodeUserNotFound, "User not found"},
+	{service.ErrUserNotInTrash, http.StatusNotFound, response.CodeUserNotInTrash, "User not found in trash"},
+	{service.ErrEmailTaken, http.StatusConflict, response.CodeEmailTaken, "Email already registered"},
+	{service.ErrLastAdmin, http.StatusConflict, response.CodeLastAdmin, "Cannot remove the last active admin"},
+	{service.ErrReassignTarget, http.StatusBadRequest, response.CodeInvalidReassignTarget, "Reassign target must be an active user"},
+	{service.ErrSalesRepAmbiguous, http.StatusConflict, response.CodeSalesRepAmbiguous, "User name is not unique"},
+	{service.ErrInvalidCredentials, http.StatusUnauthorized, response.CodeInvalidCredentials, "Email or password is incorrect"},
+	{service.ErrResetTokenInvalid, http.StatusBadRequest, response.CodeResetTokenInvalid, "Reset token invalid or expired"},
+}
+
+// respondError menulis response untuk error dari service. Error yang tidak dikenal di-log lalu menjadi 500
+// dengan pesan fallback.
+func respondError(c *gin.Context, err error, fallback string) {
+	var ve *service.ValidationError
+	if errors.As(err, &ve) {
+		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(ve.Err))
+		return
+	}
+	for _, r := range errorResponses {
+		if errors.Is(err, r.err) {
+			response.Fail(c, r.status, r.code, r.message, nil)
+			return
+		}
+	}
+	logging.FromContext(c.Request.Context()).Error(fallback, "error", err)
+	response.InternalError(c, fallback)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+func etag(version uint) string { return `"` + strconv.FormatUint(uint64(version), 10) + `"` }
+
+func setETag(c *gin.Context, version uint) { c.Header("ETag", etag(version)) }
+
+// checkIfMatch memastikan header If-Match cocok dengan version saat ini.
+// Header kosong -> 428, tidak cocok -> 412 beserta representasi terbaru.
+func checkIfMatch(c *gin.Context, version uint, current interface{}) bool {
+	h := c.GetHeader("If-Match")
+	if h == "" {
+		response.PreconditionRequired(c, "If-Match header is required")
+		return false
+	}
+	want := etag(version)
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == want {
+			return true
+		}
+	}
+	preconditionFailed(c, version, current)
+	return false
+}
+
+func preconditionFailed(c *gin.Context, version uint, current interface{}) {
+	setETag(c, version)
+	response.PreconditionFailed(c, "Resource has been modified", current)
+}
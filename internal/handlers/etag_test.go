@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	current := map[string]string{"name": "terbaru"}
+	for _, tc := range []struct {
+		name    string
+		ifMatch string
+		ok      bool
+		status  int
+	}{
+		{"missing", "", false, http.StatusPreconditionRequired},
+		{"match", `"3"`, true, http.StatusOK},
+		{"weak match", `W/"3"`, true, http.StatusOK},
+		{"one of list", `"1", "3"`, true, http.StatusOK},
+		{"wildcard", "*", true, http.StatusOK},
+		{"stale", `"2"`, false, http.StatusPreconditionFailed},
+		{"unquoted", "3", false, http.StatusPreconditionFailed},
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/leads/1", nil)
+		if tc.ifMatch != "" {
+			c.Request.Header.Set("If-Match", tc.ifMatch)
+		}
+		if ok := checkIfMatch(c, 3, current); ok != tc.ok || w.Code != tc.status {
+			t.Errorf("%s: ok = %v, status %d; want %v, %d", tc.name, ok, w.Code, tc.ok, tc.status)
+			continue
+		}
+		if tc.status != http.StatusPreconditionFailed {
+			continue
+		}
+		// 412 membawa ETag dan representasi terbaru supaya client bisa merge ulang.
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Data["name"] != "terbaru" || w.Header().Get("ETag") != `"3"` {
+			t.Errorf("%s: ETag %q, body %s", tc.name, w.Header().Get("ETag"), w.Body)
+		}
+	}
+}
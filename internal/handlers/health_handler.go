@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/buildinfo"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+type HealthHandler struct {
+	Checks []health.Check
+}
+
+func NewHealthHandler(checks ...health.Check) *HealthHandler {
+	return &HealthHandler{Checks: checks}
+}
+
+// Live liveness: proses hidup dan bisa melayani HTTP. Sengaja tidak mengecek dependency supaya database
+// yang down tidak membuat orchestrator me-restart semua instance.
+func (h *HealthHandler) Live(c *gin.Context) {
+	response.OK(c, gin.H{"status": health.StatusOK}, "Alive")
+}
+
+// Ready readiness: 503 kalau ada check yang gagal atau server sedang draining.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	rep := health.Run(c.Request.Context(), h.Checks)
+	if !rep.OK() {
+		response.Fail(c, http.StatusServiceUnavailable, response.CodeNotReady, "Service not ready", rep)
+		return
+	}
+	response.OK(c, rep, "Ready")
+}
+
+func (h *HealthHandler) Version(c *gin.Context) {
+	response.OK(c, buildinfo.Get(), "Build info")
+}
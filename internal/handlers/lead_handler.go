@@ -1,250 +1,158 @@
 package handlers
 
 import (
-	"strconv"
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
 )
 
 type LeadHandler struct {
-	DB *gorm.DB
+	Leads *service.LeadService
 }
 
-func NewLeadHandler(db *gorm.DB) *LeadHandler { return &LeadHandler{DB: db} }
-
-type leadPayload struct {
-	CompanyName string  `json:"company_name" binding:"required"`
-	ContactName string  `json:"contact_name" binding:"required"`
-	Email       string  `json:"email" binding:"required,email"`
-	Phone       *string `json:"phone"`
-	Source      *string `json:"source"`
-	Industry    *string `json:"industry"`
-	Region      *string `json:"region"`
-	SalesRep    *string `json:"sales_rep"`
-	Status      *string `json:"status"`
-	Notes       *string `json:"notes"`
+func NewLeadHandler(leads *service.LeadService) *LeadHandler {
+	return &LeadHandler{Leads: leads}
 }
 
 func (h *LeadHandler) Create(c *gin.Context) {
-	var p leadPayload
-	if err := c.ShouldBindJSON(&p); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+	var in service.LeadInput
+	if !bindJSON(c, &in) {
 		return
 	}
-	lead := models.Lead{
-		CompanyName: p.CompanyName,
-		ContactName: p.ContactName,
-		Email:       p.Email,
-		Phone:       p.Phone,
-		Source:      p.Source,
-		Industry:    p.Industry,
-		Region:      p.Region,
-		SalesRep:    p.SalesRep,
-		Status:      p.Status,
-		Notes:       p.Notes,
-	}
-	if err := h.DB.Create(&lead).Error; err != nil {
-		response.InternalError(c, "Failed to create lead")
+	lead, err := h.Leads.Create(c.Request.Context(), in)
+	if err != nil {
+		respondError(c, err, "Failed to create lead")
 		return
 	}
+	setETag(c, lead.Version)
 	response.Created(c, gin.H{
 		"id": lead.LeadID, "company_name": lead.CompanyName, "status": lead.Status, "created_at": lead.CreatedAt,
 	}, "Lead created")
 }
 
 func (h *LeadHandler) List(c *gin.Context) {
-	var leads []models.Lead
-
-	q := h.DB.Model(&models.Lead{})
-	if v := c.Query("status"); v != "" {
-		q = q.Where("status = ?", v)
+	query, p, ok := listFrom(c, repository.LeadFields, 10)
+	if !ok {
+		return
 	}
-	if v := c.Query("source"); v != "" {
-		q = q.Where("source = ?", v)
+	f := repository.LeadFilter{Q: c.Query("q"), Query: query}
+	leads, info, err := h.Leads.List(c.Request.Context(), f, p)
+	if err != nil {
+		respondError(c, err, "Failed to list leads")
+		return
 	}
-	if v := c.Query("q"); v != "" {
-		q = q.Where("company_name LIKE ? OR contact_name LIKE ? OR email LIKE ?", "%"+v+"%", "%"+v+"%", "%"+v+"%")
+	response.OK(c, cursorResult(leads, p, info), "Lead list")
+}
+
+func (h *LeadHandler) Get(c *gin.Context) {
+	lead, err := h.Leads.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get lead")
+		return
 	}
+	setETag(c, lead.Version)
+	response.OK(c, lead, "Lead detail")
+}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	per, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if page < 1 {
-		page = 1
+func (h *LeadHandler) Update(c *gin.Context) {
+	lead, err := h.Leads.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get lead")
+		return
 	}
-	if per < 1 {
-		per = 10
+	if !checkIfMatch(c, lead.Version, lead) {
+		return
 	}
-	offset := (page - 1) * per
-
-	var total int64
-	q.Count(&total)
-
-	if err := q.Order("created_at DESC").Limit(per).Offset(offset).Find(&leads).Error; err != nil {
-		response.InternalError(c, "Failed to list leads")
+	var in service.LeadInput
+	if !bindJSON(c, &in) {
 		return
 	}
-	response.OK(c, response.List(leads, page, per, total), "Lead list")
+	h.save(c, lead, in)
 }
 
-func (h *LeadHandler) Get(c *gin.Context) {
-	id := c.Param("id")
-	var lead models.Lead
-	if err := h.DB.First(&lead, id).Error; err != nil {
-		response.NotFound(c, "Lead not found")
+// PATCH /leads/:id  (application/merge-patch+json)
+func (h *LeadHandler) Patch(c *gin.Context) {
+	lead, err := h.Leads.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get lead")
 		return
 	}
-	response.OK(c, lead, "Lead detail")
+	if !checkIfMatch(c, lead.Version, lead) {
+		return
+	}
+	var in service.LeadInput
+	if !bindMergePatch(c, service.LeadInputFrom(lead), &in) {
+		return
+	}
+	h.save(c, lead, in)
 }
 
-func (h *LeadHandler) Update(c *gin.Context) {
-	id := c.Param("id")
-	var lead models.Lead
-	if err := h.DB.First(&lead, id).Error; err != nil {
-		response.NotFound(c, "Lead not found")
-		return
-	}
-	var p leadPayload
-	if err := c.ShouldBindJSON(&p); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
-		return
-	}
-	lead.CompanyName = p.CompanyName
-	lead.ContactName = p.ContactName
-	lead.Email = p.Email
-	lead.Phone = p.Phone
-	lead.Source = p.Source
-	lead.Industry = p.Industry
-	lead.Region = p.Region
-	lead.SalesRep = p.SalesRep
-	lead.Status = p.Status
-	lead.Notes = p.Notes
-
-	if err := h.DB.Save(&lead).Error; err != nil {
-		response.InternalError(c, "Failed to update lead")
+func (h *LeadHandler) save(c *gin.Context, before models.Lead, in service.LeadInput) {
+	lead, err := h.Leads.Update(c.Request.Context(), before, in)
+	if errors.Is(err, service.ErrVersionConflict) {
+		current, _ := h.Leads.Get(c.Request.Context(), before.LeadID)
+		preconditionFailed(c, current.Version, current)
+		return
+	}
+	if err != nil {
+		respondError(c, err, "Failed to update lead")
 		return
 	}
+	setETag(c, lead.Version)
 	response.OK(c, lead, "Lead updated")
 }
 
+// Soft delete, deals milik lead ikut masuk trash.
 func (h *LeadHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.DB.Delete(&models.Lead{}, id).Error; err != nil {
-		response.InternalError(c, "Failed to delete lead")
+	if _, err := h.Leads.Delete(c.Request.Context(), paramID(c)); err != nil {
+		respondError(c, err, "Failed to delete lead")
 		return
 	}
 	response.NoContent(c, "Lead deleted")
 }
 
-// GET /leads/summary?from=YYYY-MM-DD&to=YYYY-MM-DD
-func (h *LeadHandler) Summary(c *gin.Context) {
-	from := c.Query("from")
-	to := c.Query("to")
-	var fromT, toT time.Time
-	var err error
-
-	if from != "" {
-		fromT, err = time.Parse("2006-01-02", from)
-		if err != nil {
-			from = ""
-		}
-	}
-	if to != "" {
-		toT, err = time.Parse("2006-01-02", to)
-		if err != nil {
-			to = ""
-		}
-	}
-
-	q := h.DB.Model(&models.Lead{})
-	if from != "" {
-		q = q.Where("created_at >= ?", fromT)
-	}
-	if to != "" {
-		q = q.Where("created_at < ?", toT.Add(24*time.Hour))
+// GET /leads/trash
+func (h *LeadHandler) Trash(c *gin.Context) {
+	p := pageFrom(c, 10)
+	leads, total, err := h.Leads.Trash(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err, "Failed to list trash")
+		return
 	}
+	response.OK(c, listResult(leads, p, total), "Lead trash")
+}
 
-	// total leads
-	var total int64
-	q.Count(&total)
-
-	type rowAgg struct {
-		Name  *string `gorm:"column:name"`
-		Count int64   `gorm:"column:count"`
-	}
-	by := func(field string) map[string]int64 {
-		var rows []rowAgg
-		res := map[string]int64{}
-		sub := h.DB.Model(&models.Lead{})
-		if from != "" {
-			sub = sub.Where("created_at >= ?", fromT)
-		}
-		if to != "" {
-			sub = sub.Where("created_at < ?", toT.Add(24*time.Hour))
-		}
-		sub.Select(field+" AS name, COUNT(*) AS count").Group(field).Scan(&rows)
-		for _, r := range rows {
-			k := ""
-			if r.Name != nil {
-				k = *r.Name
-			}
-			res[k] = r.Count
-		}
-		return res
+// POST /leads/:id/restore
+func (h *LeadHandler) Restore(c *gin.Context) {
+	lead, err := h.Leads.Restore(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to restore lead")
+		return
 	}
+	response.OK(c, lead, "Lead restored")
+}
 
-	byStatus := by("status")
-	bySource := by("source")
-	byRegion := by("region")
-
-	// deals aggregate (by closed_at)
-	type DealAgg struct {
-		Count int64
-		Total int64   `gorm:"column:total"`
-		Avg   float64 `gorm:"column:avg"`
-	}
-	var agg DealAgg
-	dq := h.DB.Model(&models.Deal{})
-	if from != "" {
-		dq = dq.Where("closed_at >= ?", fromT)
+// GET /leads/summary?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Tanggal yang tidak valid diabaikan (tidak membatasi).
+func (h *LeadHandler) Summary(c *gin.Context) {
+	var r repository.DateRange
+	if t, err := time.Parse("2006-01-02", c.Query("from")); err == nil {
+		r.From = &t
 	}
-	if to != "" {
-		dq = dq.Where("closed_at < ?", toT.Add(24*time.Hour))
+	if t, err := time.Parse("2006-01-02", c.Query("to")); err == nil {
+		end := t.Add(24 * time.Hour)
+		r.To = &end
 	}
-	dq.Select("COUNT(*) as count, COALESCE(SUM(amount_idr),0) as total, COALESCE(AVG(term_months),0) as avg").Scan(&agg)
-
-	var byStageRows []rowAgg
-	dqStages := h.DB.Model(&models.Deal{})
-	if from != "" {
-		dqStages = dqStages.Where("closed_at >= ?", fromT)
-	}
-	if to != "" {
-		dqStages = dqStages.Where("closed_at < ?", toT.Add(24*time.Hour))
-	}
-	dqStages.Select("stage AS name, COUNT(*) AS count").Group("stage").Scan(&byStageRows)
-	stageMap := map[string]int64{}
-	for _, r := range byStageRows {
-		k := ""
-		if r.Name != nil {
-			k = *r.Name
-		}
-		stageMap[k] = r.Count
+	sum, err := h.Leads.Summary(c.Request.Context(), r)
+	if err != nil {
+		respondError(c, err, "Failed to build lead summary")
+		return
 	}
-
-	response.OK(c, gin.H{
-		"total_leads": total,
-		"by_status":   byStatus,
-		"by_source":   bySource,
-		"by_region":   byRegion,
-		"deals": gin.H{
-			"count":            agg.Count,
-			"total_amount_idr": agg.Total,
-			"avg_term_months":  agg.Avg,
-			"by_stage":         stageMap,
-		},
-	}, "Lead summary")
+	response.OK(c, sum, "Lead summary")
 }
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+const mimeMergePatch = "application/merge-patch+json"
+
+// mergePatch menerapkan JSON Merge Patch (RFC 7396): null menghapus key, object di-merge rekursif,
+// selain itu nilai patch menggantikan nilai lama.
+func mergePatch(doc, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	dm, ok := doc.(map[string]interface{})
+	if !ok {
+		dm = map[string]interface{}{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(dm, k)
+			continue
+		}
+		dm[k] = mergePatch(dm[k], v)
+	}
+	return dm
+}
+
+// bindMergePatch menerapkan body request ke `current` lalu decode hasilnya ke dst. Field yang tidak
+// dikenal ditolak; validasi isi dilakukan service. Response error sudah ditulis kalau return false.
+func bindMergePatch(c *gin.Context, current, dst interface{}) bool {
+	if ct := c.ContentType(); ct != mimeMergePatch && ct != binding.MIMEJSON {
+		response.UnsupportedMediaType(c, "Content-Type must be "+mimeMergePatch)
+		return false
+	}
+	raw, err := c.GetRawData()
+	if tooLarge(err) {
+		response.PayloadTooLarge(c, "")
+		return false
+	}
+	if err != nil {
+		response.BadRequest(c, "Failed to read request body", nil)
+		return false
+	}
+	var patch interface{}
+	if err := json.Unmarshal(raw, &patch); err != nil {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidJSON, "Invalid JSON", nil)
+		return false
+	}
+	if _, ok := patch.(map[string]interface{}); !ok {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidJSON, "Merge patch must be a JSON object", nil)
+		return false
+	}
+
+	base, err := json.Marshal(current)
+	if err != nil {
+		respondError(c, err, "Failed to apply patch")
+		return false
+	}
+	var doc interface{}
+	_ = json.Unmarshal(base, &doc)
+	merged, _ := json.Marshal(mergePatch(doc, patch))
+
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+		return false
+	}
+	return true
+}
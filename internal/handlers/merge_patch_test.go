@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+func TestMergePatch(t *testing.T) {
+	doc := map[string]interface{}{"a": "x", "b": map[string]interface{}{"c": 1.0, "d": 2.0}, "e": "y"}
+	patch := map[string]interface{}{"a": "z", "b": map[string]interface{}{"d": nil}, "e": nil, "f": []interface{}{1.0}}
+	want := map[string]interface{}{"a": "z", "b": map[string]interface{}{"c": 1.0}, "f": []interface{}{1.0}}
+	if got := mergePatch(doc, patch); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergePatch = %v, want %v", got, want)
+	}
+	// Patch selain object menggantikan dokumen seluruhnya (RFC 7396).
+	if got := mergePatch(doc, "baru"); got != "baru" {
+		t.Fatalf("non-object patch = %v", got)
+	}
+}
+
+func TestBindMergePatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	phone, notes := "0812", "lama"
+	current := service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id", Phone: &phone, Notes: &notes}
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		ok          bool
+		status      int
+	}{
+		{"set, keep and clear", mimeMergePatch, `{"company_name":"PT B","phone":null}`, true, http.StatusOK},
+		{"plain json", "application/json", `{}`, true, http.StatusOK},
+		{"wrong content type", "text/plain", `{}`, false, http.StatusUnsupportedMediaType},
+		{"not an object", mimeMergePatch, `["a"]`, false, http.StatusBadRequest},
+		{"unknown field", mimeMergePatch, `{"nickname":"A"}`, false, http.StatusUnprocessableEntity},
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPatch, "/leads/1", strings.NewReader(tc.body))
+		c.Request.Header.Set("Content-Type", tc.contentType)
+		var got service.LeadInput
+		if ok := bindMergePatch(c, current, &got); ok != tc.ok || w.Code != tc.status {
+			t.Errorf("%s: ok = %v, status %d; want %v, %d (%s)", tc.name, ok, w.Code, tc.ok, tc.status, w.Body)
+			continue
+		}
+		if tc.name == "set, keep and clear" &&
+			(got.CompanyName != "PT B" || got.Phone != nil || got.Notes == nil || *got.Notes != "lama" || got.Email != "ani@a.id") {
+			t.Errorf("%s: merged = %+v", tc.name, got)
+		}
+	}
+}
@@ -1,143 +1,138 @@
 package handlers
 
 import (
-	"strconv"
-	"time"
+	"errors"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
 )
 
-type ProjectHandler struct{ DB *gorm.DB }
-
-func NewProjectHandler(db *gorm.DB) *ProjectHandler { return &ProjectHandler{DB: db} }
-
-type projectPayload struct {
-	Name        string  `json:"name" binding:"required,min=2"`
-	Description *string `json:"description"`
-	Status      *string `json:"status" binding:"omitempty,oneof=planned in_progress on_hold done canceled"`
-	StartDate   *string `json:"start_date"` // "YYYY-MM-DD"
-	EndDate     *string `json:"end_date"`
-	OwnerUserID *uint   `json:"owner_user_id"`
+type ProjectHandler struct {
+	Projects *service.ProjectService
 }
 
-func parseDatePtr(s *string) *time.Time {
-	if s == nil || *s == "" {
-		return nil
-	}
-	t, err := time.Parse("2006-01-02", *s)
-	if err != nil {
-		return nil
-	}
-	return &t
+func NewProjectHandler(projects *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{Projects: projects}
 }
 
 func (h *ProjectHandler) Create(c *gin.Context) {
-	var p projectPayload
-	if err := c.ShouldBindJSON(&p); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+	var in service.ProjectInput
+	if !bindJSON(c, &in) {
 		return
 	}
-	status := "planned"
-	if p.Status != nil {
-		status = *p.Status
-	}
-	proj := models.Project{
-		Name:        p.Name,
-		Description: p.Description,
-		Status:      status,
-		StartDate:   parseDatePtr(p.StartDate),
-		EndDate:     parseDatePtr(p.EndDate),
-		OwnerUserID: p.OwnerUserID,
-	}
-	if err := h.DB.Create(&proj).Error; err != nil {
-		response.InternalError(c, "Failed to create project")
+	proj, err := h.Projects.Create(c.Request.Context(), in)
+	if err != nil {
+		respondError(c, err, "Failed to create project")
 		return
 	}
+	setETag(c, proj.Version)
 	response.Created(c, proj, "Project created")
 }
 
 func (h *ProjectHandler) List(c *gin.Context) {
-	var items []models.Project
-	q := h.DB.Model(&models.Project{})
-	if v := c.Query("status"); v != "" {
-		q = q.Where("status = ?", v)
-	}
-	if v := c.Query("q"); v != "" {
-		q = q.Where("name LIKE ? OR description LIKE ?", "%"+v+"%", "%"+v+"%")
-	}
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	per, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if page < 1 {
-		page = 1
-	}
-	if per < 1 {
-		per = 10
+	query, p, ok := listFrom(c, repository.ProjectFields, 10)
+	if !ok {
+		return
 	}
-	var total int64
-	q.Count(&total)
-	if err := q.Order("created_at DESC").Limit(per).Offset((page-1)*per).Find(&items).Error; err != nil {
-		response.InternalError(c, "Failed to list projects")
+	f := repository.ProjectFilter{Q: c.Query("q"), Query: query}
+	items, info, err := h.Projects.List(c.Request.Context(), f, p)
+	if err != nil {
+		respondError(c, err, "Failed to list projects")
 		return
 	}
-	response.OK(c, response.List(items, page, per, total), "Project list")
+	response.OK(c, cursorResult(items, p, info), "Project list")
 }
 
 func (h *ProjectHandler) Get(c *gin.Context) {
-	id := c.Param("id")
-	var item models.Project
-	if err := h.DB.First(&item, id).Error; err != nil {
-		response.NotFound(c, "Project not found")
+	item, err := h.Projects.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get project")
 		return
 	}
+	setETag(c, item.Version)
 	response.OK(c, item, "Project detail")
 }
 
 func (h *ProjectHandler) Update(c *gin.Context) {
-	id := c.Param("id")
-	var item models.Project
-	if err := h.DB.First(&item, id).Error; err != nil {
-		response.NotFound(c, "Project not found")
+	item, err := h.Projects.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get project")
 		return
 	}
-	var p projectPayload
-	if err := c.ShouldBindJSON(&p); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+	if !checkIfMatch(c, item.Version, item) {
 		return
 	}
-	if p.Name != "" {
-		item.Name = p.Name
-	}
-	if p.Description != nil {
-		item.Description = p.Description
+	var in service.ProjectInput
+	if !bindJSON(c, &in) {
+		return
 	}
-	if p.Status != nil {
-		item.Status = *p.Status
+	updated, err := h.Projects.Update(c.Request.Context(), item, in)
+	h.saved(c, item, updated, err)
+}
+
+// PATCH /projects/:id  (application/merge-patch+json)
+// Beda dengan PUT, field yang di-set null di patch benar-benar dikosongkan.
+func (h *ProjectHandler) Patch(c *gin.Context) {
+	item, err := h.Projects.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get project")
+		return
 	}
-	if p.StartDate != nil {
-		item.StartDate = parseDatePtr(p.StartDate)
+	if !checkIfMatch(c, item.Version, item) {
+		return
 	}
-	if p.EndDate != nil {
-		item.EndDate = parseDatePtr(p.EndDate)
+	var in service.ProjectInput
+	if !bindMergePatch(c, service.ProjectInputFrom(item), &in) {
+		return
 	}
-	if p.OwnerUserID != nil {
-		item.OwnerUserID = p.OwnerUserID
+	updated, err := h.Projects.Replace(c.Request.Context(), item, in)
+	h.saved(c, item, updated, err)
+}
+
+// saved menulis response hasil Update/Replace.
+func (h *ProjectHandler) saved(c *gin.Context, before, item models.Project, err error) {
+	if errors.Is(err, service.ErrVersionConflict) {
+		current, _ := h.Projects.Get(c.Request.Context(), before.ID)
+		preconditionFailed(c, current.Version, current)
+		return
 	}
-	if err := h.DB.Save(&item).Error; err != nil {
-		response.InternalError(c, "Failed to update project")
+	if err != nil {
+		respondError(c, err, "Failed to update project")
 		return
 	}
+	setETag(c, item.Version)
 	response.OK(c, item, "Project updated")
 }
 
 func (h *ProjectHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.DB.Delete(&models.Project{}, id).Error; err != nil {
-		response.InternalError(c, "Failed to delete project")
+	if _, err := h.Projects.Delete(c.Request.Context(), paramID(c)); err != nil {
+		respondError(c, err, "Failed to delete project")
 		return
 	}
 	response.NoContent(c, "Project deleted")
 }
+
+// GET /projects/trash
+func (h *ProjectHandler) Trash(c *gin.Context) {
+	p := pageFrom(c, 10)
+	items, total, err := h.Projects.Trash(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err, "Failed to list trash")
+		return
+	}
+	response.OK(c, listResult(items, p, total), "Project trash")
+}
+
+// POST /projects/:id/restore
+func (h *ProjectHandler) Restore(c *gin.Context) {
+	item, err := h.Projects.Restore(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to restore project")
+		return
+	}
+	response.OK(c, item, "Project restored")
+}
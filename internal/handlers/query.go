@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// maxInValues batas jumlah nilai di field[in]=a,b,c.
+const maxInValues = 50
+
+// filterKey parameter filter dengan operator, mis. created_at[gte].
+var filterKey = regexp.MustCompile(`^([a-z_]+)\[([a-z]+)\]$`)
+
+// listParams parameter list yang bukan filter field.
+var listParams = map[string]bool{"page": true, "per_page": true, "cursor": true, "include_total": true, "q": true, "sort": true}
+
+// queryFrom membaca ?sort=-created_at,company_name dan filter field=nilai / field[op]=nilai sesuai whitelist
+// fields. Parameter tanpa [op] yang bukan nama field diabaikan; field, operator atau nilai yang tidak valid
+// dijawab 400 INVALID_QUERY dengan daftar parameter yang salah. Response sudah ditulis kalau return false.
+func queryFrom(c *gin.Context, fields []repository.Field) (repository.Query, bool) {
+	var q repository.Query
+	var errs []response.FieldError
+	values := c.Request.URL.Query()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if listParams[key] {
+			continue
+		}
+		name, op := key, repository.OpEq
+		if m := filterKey.FindStringSubmatch(key); m != nil {
+			name, op = m[1], repository.Op(m[2])
+		}
+		f, ok := fieldByName(fields, name)
+		switch {
+		case !ok && name == key:
+			continue
+		case !ok:
+			errs = append(errs, response.QueryError(key, "field", "is not a filterable field"))
+			continue
+		case !f.Allows(op):
+			errs = append(errs, response.QueryError(key, "operator", "does not support operator %s", string(op)))
+			continue
+		}
+		for _, raw := range values[key] {
+			cond, fe := parseCond(key, f, op, raw)
+			if fe != nil {
+				errs = append(errs, *fe)
+				continue
+			}
+			q.Where = append(q.Where, cond)
+		}
+	}
+
+	if raw := c.Query("sort"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			name := strings.TrimPrefix(part, "-")
+			f, ok := fieldByName(fields, name)
+			if !ok || !f.Sortable {
+				errs = append(errs, response.QueryError("sort", "sortable", "%s is not a sortable field", name))
+				continue
+			}
+			q.Sort = append(q.Sort, repository.Sort{Column: f.Column, Desc: name != part})
+		}
+	}
+
+	if len(errs) > 0 {
+		response.InvalidQuery(c, "", errs)
+		return q, false
+	}
+	return q, true
+}
+
+func fieldByName(fields []repository.Field, name string) (repository.Field, bool) {
+	i := slices.IndexFunc(fields, func(f repository.Field) bool { return f.Name == name })
+	if i < 0 {
+		return repository.Field{}, false
+	}
+	return fields[i], true
+}
+
+// parseCond mengubah satu nilai parameter menjadi Cond. Tanggal tanpa jam untuk lte/gt mencakup seluruh
+// hari itu: created_at[lte]=2024-01-31 menjadi < 2024-02-01.
+func parseCond(key string, f repository.Field, op repository.Op, raw string) (repository.Cond, *response.FieldError) {
+	cond := repository.Cond{Column: f.Column, Op: op}
+	fail := func(rule, msg string, args ...interface{}) (repository.Cond, *response.FieldError) {
+		fe := response.QueryError(key, rule, msg, args...)
+		return cond, &fe
+	}
+	switch op {
+	case repository.OpNull:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fail("type", "must be a boolean")
+		}
+		cond.Values = []any{isNull}
+		return cond, nil
+	case repository.OpIn:
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxInValues {
+			return fail("max", "must be at most %s items", strconv.Itoa(maxInValues))
+		}
+		for _, part := range parts {
+			v, _, msg := parseValue(f.Kind, strings.TrimSpace(part))
+			if msg != "" {
+				return fail("type", msg)
+			}
+			cond.Values = append(cond.Values, v)
+		}
+		return cond, nil
+	}
+
+	v, dateOnly, msg := parseValue(f.Kind, raw)
+	if msg != "" {
+		return fail("type", msg)
+	}
+	if dateOnly {
+		switch op {
+		case repository.OpLte:
+			cond.Op, v = repository.OpLt, v.(time.Time).AddDate(0, 0, 1)
+		case repository.OpGt:
+			cond.Op, v = repository.OpGte, v.(time.Time).AddDate(0, 0, 1)
+		}
+	}
+	cond.Values = []any{v}
+	return cond, nil
+}
+
+// parseValue nilai sesuai kind; msg berisi key pesan error kalau tidak valid. dateOnly true untuk tanggal
+// YYYY-MM-DD (waktu UTC 00:00).
+func parseValue(kind repository.Kind, raw string) (v any, dateOnly bool, msg string) {
+	switch kind {
+	case repository.KindInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, false, "must be an integer"
+		}
+		return n, false, ""
+	case repository.KindTime:
+		if t, err := time.Parse(time.DateOnly, raw); err == nil {
+			return t, true, ""
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, false, "must be a date or RFC 3339 time"
+		}
+		return t, false, ""
+	}
+	return raw, false, ""
+}
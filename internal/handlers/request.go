@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// bindJSON hanya men-decode body; validasi dilakukan service. Response sudah ditulis kalau return false.
+func bindJSON(c *gin.Context, dst interface{}) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(dst); err != nil {
+		if tooLarge(err) {
+			response.PayloadTooLarge(c, "")
+			return false
+		}
+		if response.IsMalformedJSON(err) {
+			response.Fail(c, http.StatusUnprocessableEntity, response.CodeInvalidJSON, "Request body must be valid JSON", nil)
+			return false
+		}
+		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+		return false
+	}
+	return true
+}
+
+// tooLarge true kalau body dipotong middleware.BodyLimit (body chunked tanpa Content-Length).
+func tooLarge(err error) bool {
+	var e *http.MaxBytesError
+	return errors.As(err, &e)
+}
+
+// paramID membaca :id sebagai angka. Id yang tidak valid menjadi 0, sehingga service mengembalikan not found.
+func paramID(c *gin.Context) uint {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+// maxPerPage batas atas ?per_page=; nilai lebih besar dipotong ke sini.
+const maxPerPage = 100
+
+// pageFrom membaca ?page=, ?per_page= dan ?include_total=, nilai tidak valid kembali ke default.
+func pageFrom(c *gin.Context, defPer int) repository.Page {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	per, _ := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defPer)))
+	if page < 1 {
+		page = 1
+	}
+	if per < 1 {
+		per = defPer
+	}
+	withTotal, _ := strconv.ParseBool(c.Query("include_total"))
+	return repository.Page{Page: page, PerPage: min(per, maxPerPage), WithTotal: withTotal}
+}
+
+// listFrom membaca parameter list keyset: filter dan sort (lihat queryFrom), pageFrom, dan ?cursor= dari
+// next_cursor/prev_cursor response sebelumnya. Cursor hanya berlaku untuk urutan created_at; cursor rusak
+// dijawab 400. Response sudah ditulis kalau return false.
+func listFrom(c *gin.Context, fields []repository.Field, defPer int) (repository.Query, repository.Page, bool) {
+	q, ok := queryFrom(c, fields)
+	if !ok {
+		return q, repository.Page{}, false
+	}
+	p := pageFrom(c, defPer)
+	raw := c.Query("cursor")
+	if raw == "" {
+		return q, p, true
+	}
+	if _, keyset := q.Keyset(); !keyset {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidQuery, "Cursor pagination requires sort by created_at", nil)
+		return q, p, false
+	}
+	cur, prev, err := decodeCursor(raw)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidQuery, "Invalid cursor", nil)
+		return q, p, false
+	}
+	if prev {
+		p.Before = &cur
+	} else {
+		p.After = &cur
+	}
+	p.Page = 0
+	return q, p, true
+}
+
+func listResult(items interface{}, p repository.Page, total int64) response.ListResult {
+	return response.List(items, p.Page, p.PerPage, total)
+}
+
+func cursorResult(items interface{}, p repository.Page, info repository.PageInfo) response.ListResult {
+	return response.ListResult{
+		Items: items,
+		Pagination: response.Pagination{
+			Page:       p.Page,
+			PerPage:    p.PerPage,
+			Total:      info.Total,
+			NextCursor: encodeCursor(info.Next, false),
+			PrevCursor: encodeCursor(info.Prev, true),
+		},
+	}
+}
+
+// cursorToken isi cursor sebelum di-base64. Klien memperlakukannya opaque; formatnya boleh berubah.
+type cursorToken struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+	Prev      bool      `json:"p,omitempty"`
+}
+
+func encodeCursor(cur *repository.Cursor, prev bool) string {
+	if cur == nil {
+		return ""
+	}
+	b, _ := json.Marshal(cursorToken{CreatedAt: cur.CreatedAt, ID: cur.ID, Prev: prev})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (repository.Cursor, bool, error) {
+	var tok cursorToken
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &tok)
+	}
+	if err == nil && (tok.ID == 0 || tok.CreatedAt.IsZero()) {
+		err = errors.New("incomplete cursor")
+	}
+	return repository.Cursor{CreatedAt: tok.CreatedAt, ID: tok.ID}, tok.Prev, err
+}
@@ -1,114 +1,149 @@
 package handlers
 
 import (
-	"strconv"
+	"errors"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
-	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
 )
 
-type UserAdminHandler struct{ DB *gorm.DB }
-
-func NewUserAdminHandler(db *gorm.DB) *UserAdminHandler { return &UserAdminHandler{DB: db} }
+type UserAdminHandler struct {
+	Users  *service.UserService
+	Purger *service.TrashService
+	// ShowResetToken mengembalikan token reset di response (test mode, belum ada mailer).
+	ShowResetToken bool
+}
 
-type adminCreateUserReq struct {
-	Name     string `json:"name" binding:"required,min=2"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"required,oneof=user admin"`
+func NewUserAdminHandler(users *service.UserService, trash *service.TrashService) *UserAdminHandler {
+	return &UserAdminHandler{Users: users, Purger: trash}
 }
 
 func (h *UserAdminHandler) Create(c *gin.Context) {
-	var req adminCreateUserReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+	var in service.CreateUserInput
+	if !bindJSON(c, &in) {
 		return
 	}
-	hash, _ := auth.HashPassword(req.Password)
-	u := models.User{Name: req.Name, Email: req.Email, PasswordHash: hash, Role: req.Role}
-	if err := h.DB.Create(&u).Error; err != nil {
-		response.Conflict(c, "Email already registered")
+	u, err := h.Users.Create(c.Request.Context(), in)
+	if err != nil {
+		respondError(c, err, "Failed to create user")
 		return
 	}
+	setETag(c, u.Version)
 	response.Created(c, u, "User created")
 }
 
 func (h *UserAdminHandler) List(c *gin.Context) {
-	var users []models.User
-	q := h.DB.Model(&models.User{})
-	if v := c.Query("q"); v != "" {
-		q = q.Where("name LIKE ? OR email LIKE ?", "%"+v+"%", "%"+v+"%")
-	}
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	per, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if page < 1 {
-		page = 1
-	}
-	if per < 1 {
-		per = 10
+	query, p, ok := listFrom(c, repository.UserFields, 10)
+	if !ok {
+		return
 	}
-	var total int64
-	q.Count(&total)
-	if err := q.Order("created_at DESC").Limit(per).Offset((page-1)*per).Find(&users).Error; err != nil {
-		response.InternalError(c, "Failed to list users")
+	f := repository.UserFilter{Q: c.Query("q"), Query: query}
+	users, info, err := h.Users.List(c.Request.Context(), f, p)
+	if err != nil {
+		respondError(c, err, "Failed to list users")
 		return
 	}
-	response.OK(c, response.List(users, page, per, total), "User list")
+	response.OK(c, cursorResult(users, p, info), "User list")
 }
 
 func (h *UserAdminHandler) Get(c *gin.Context) {
-	id := c.Param("id")
-	var u models.User
-	if err := h.DB.First(&u, id).Error; err != nil {
-		response.NotFound(c, "User not found")
+	u, ok := h.findUser(c)
+	if !ok {
 		return
 	}
+	setETag(c, u.Version)
 	response.OK(c, u, "User detail")
 }
 
-type adminUpdateUserReq struct {
-	Name  *string `json:"name"`
-	Email *string `json:"email" binding:"omitempty,email"`
-	Role  *string `json:"role" binding:"omitempty,oneof=user admin"`
-}
-
 func (h *UserAdminHandler) Update(c *gin.Context) {
-	id := c.Param("id")
-	var u models.User
-	if err := h.DB.First(&u, id).Error; err != nil {
-		response.NotFound(c, "User not found")
+	u, ok := h.findUser(c)
+	if !ok {
 		return
 	}
-	var req adminUpdateUserReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.UnprocessableEntity(c, "Validation Error", response.ExtractValidationErrors(err))
+	if !checkIfMatch(c, u.Version, u) {
 		return
 	}
-	if req.Name != nil {
-		u.Name = *req.Name
+	var in service.UpdateUserInput
+	if !bindJSON(c, &in) {
+		return
 	}
-	if req.Email != nil {
-		u.Email = *req.Email
+	updated, err := h.Users.Update(c.Request.Context(), u, in)
+	h.saved(c, u, updated, err)
+}
+
+// PATCH /admin/users/:id  (application/merge-patch+json)
+func (h *UserAdminHandler) Patch(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(c, u.Version, u) {
+		return
+	}
+	var doc service.UserDoc
+	if !bindMergePatch(c, service.UserDocFrom(u), &doc) {
+		return
 	}
-	if req.Role != nil {
-		u.Role = *req.Role
+	updated, err := h.Users.Replace(c.Request.Context(), u, doc)
+	h.saved(c, u, updated, err)
+}
+
+// saved menulis response hasil Update/Replace.
+func (h *UserAdminHandler) saved(c *gin.Context, before, u models.User, err error) {
+	if errors.Is(err, service.ErrVersionConflict) {
+		current, _ := h.Users.Get(c.Request.Context(), before.ID)
+		preconditionFailed(c, current.Version, current)
+		return
 	}
-	if err := h.DB.Save(&u).Error; err != nil {
-		response.InternalError(c, "Failed to update user")
+	if err != nil {
+		respondError(c, err, "Failed to update user")
 		return
 	}
+	setETag(c, u.Version)
 	response.OK(c, u, "User updated")
 }
 
+// Soft delete: kepemilikan project dibiarkan supaya restore tidak kehilangan data,
+// owner_user_id baru dilepas saat user dipurge.
 func (h *UserAdminHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.DB.Delete(&models.User{}, id).Error; err != nil {
-		response.InternalError(c, "Failed to delete user")
+	if _, err := h.Users.Delete(c.Request.Context(), paramID(c)); err != nil {
+		respondError(c, err, "Failed to delete user")
 		return
 	}
 	response.NoContent(c, "User deleted")
 }
+
+// GET /admin/users/trash
+func (h *UserAdminHandler) Trash(c *gin.Context) {
+	p := pageFrom(c, 10)
+	users, total, err := h.Users.Trash(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err, "Failed to list trash")
+		return
+	}
+	response.OK(c, listResult(users, p, total), "User trash")
+}
+
+// POST /admin/users/:id/restore
+func (h *UserAdminHandler) Restore(c *gin.Context) {
+	u, err := h.Users.Restore(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to restore user")
+		return
+	}
+	response.OK(c, u, "User restored")
+}
+
+// POST /admin/trash/purge — hapus permanen data trash yang lebih tua dari retention.
+func (h *UserAdminHandler) PurgeTrash(c *gin.Context) {
+	before, n, err := h.Purger.Purge(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to purge trash")
+		return
+	}
+	response.OK(c, gin.H{"purged": n, "before": before}, "Trash purged")
+}
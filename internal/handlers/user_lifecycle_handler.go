@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+func (h *UserAdminHandler) findUser(c *gin.Context) (models.User, bool) {
+	u, err := h.Users.Get(c.Request.Context(), paramID(c))
+	if err != nil {
+		respondError(c, err, "Failed to get user")
+		return u, false
+	}
+	return u, true
+}
+
+// POST /admin/users/:id/suspend
+func (h *UserAdminHandler) Suspend(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	after, err := h.Users.Suspend(c.Request.Context(), u)
+	if err != nil {
+		respondError(c, err, "Failed to suspend user")
+		return
+	}
+	response.OK(c, after, "User suspended")
+}
+
+// POST /admin/users/:id/deactivate  body (opsional): {"reassign_to": 2}
+func (h *UserAdminHandler) Deactivate(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	var in service.DeactivateInput
+	if c.Request.Body != http.NoBody && !bindJSON(c, &in) {
+		return
+	}
+	after, err := h.Users.Deactivate(c.Request.Context(), u, in)
+	if err != nil {
+		respondError(c, err, "Failed to deactivate user")
+		return
+	}
+	response.OK(c, after, "User deactivated")
+}
+
+// POST /admin/users/:id/reactivate
+func (h *UserAdminHandler) Reactivate(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	after, err := h.Users.Reactivate(c.Request.Context(), u)
+	if err != nil {
+		respondError(c, err, "Failed to reactivate user")
+		return
+	}
+	response.OK(c, after, "User reactivated")
+}
+
+// POST /admin/users/:id/force-logout
+func (h *UserAdminHandler) ForceLogout(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	if err := h.Users.ForceLogout(c.Request.Context(), u); err != nil {
+		respondError(c, err, "Failed to revoke sessions")
+		return
+	}
+	response.OK(c, nil, "User sessions revoked")
+}
+
+// POST /admin/users/:id/reset-password
+// Semua sesi user di-revoke; token reset hanya dikembalikan kalau ShowResetToken (test mode, belum ada mailer).
+func (h *UserAdminHandler) ResetPassword(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	token, err := h.Users.ResetPassword(c.Request.Context(), u)
+	if err != nil {
+		respondError(c, err, "Failed to create reset token")
+		return
+	}
+	respondResetToken(c, token, h.ShowResetToken)
+}
+
+// respondResetToken mengembalikan token reset hanya di test mode; selain itu token tidak pernah keluar lewat API.
+func respondResetToken(c *gin.Context, token string, show bool) {
+	if !show {
+		response.OK(c, nil, "Password reset requested")
+		return
+	}
+	response.OK(c, gin.H{"reset_token": token}, "Reset token generated (test mode)")
+}
+
+// POST /admin/users/:id/reassign  body: {"to_user_id": 2}
+func (h *UserAdminHandler) Reassign(c *gin.Context) {
+	u, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+	var in service.ReassignInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	if err := h.Users.Reassign(c.Request.Context(), u, in); err != nil {
+		respondError(c, err, "Failed to reassign ownership")
+		return
+	}
+	response.OK(c, nil, "Leads and projects reassigned")
+}
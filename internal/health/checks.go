@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/migrate"
+)
+
+// Database ping ke pool database. Detail error hanya ditulis ke log karena endpoint readiness publik.
+func Database(db *gorm.DB) Check {
+	return Check{Name: "database", Fn: func(ctx context.Context) error {
+		if db == nil {
+			return errors.New("not configured")
+		}
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.PingContext(ctx)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "health: database ping failed", "error", err)
+			return errors.New("unreachable")
+		}
+		return nil
+	}}
+}
+
+// Migrations memastikan semua migration yang di-embed sudah dijalankan dan checksum-nya cocok. Migration
+// yang tercatat di database tapi tidak dikenal binary ini (missing) tidak membuat gagal: saat rolling deploy,
+// instance lama tetap ready setelah instance baru menjalankan migration berikutnya.
+func Migrations(db *gorm.DB, dialect string) Check {
+	m, err := migrator(db, dialect)
+	return Check{Name: "migrations", Fn: func(ctx context.Context) error {
+		if err != nil {
+			return err
+		}
+		sts, err := m.Status(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "health: migration status failed", "error", err)
+			return errors.New("status unavailable")
+		}
+		var pending, modified int
+		for _, st := range sts {
+			switch st.State {
+			case "pending":
+				pending++
+			case "checksum_mismatch":
+				modified++
+			}
+		}
+		switch {
+		case modified > 0:
+			return fmt.Errorf("%d migration(s) modified after being applied", modified)
+		case pending > 0:
+			return fmt.Errorf("%d migration(s) not applied", pending)
+		}
+		return nil
+	}}
+}
+
+// migrator dibuat sekali saat check dibuat, bukan setiap probe (memuat dan meng-hash semua file migration).
+func migrator(db *gorm.DB, dialect string) (*migrate.Migrator, error) {
+	if db == nil {
+		return nil, errors.New("not configured")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, dialect)
+}
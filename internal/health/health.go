@@ -0,0 +1,84 @@
+// Package health pemeriksaan liveness/readiness: daftar Check yang dijalankan paralel dengan batas waktu,
+// ditambah status draining yang dipasang saat server mulai shutdown.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Timeout batas waktu satu check; check yang lebih lama dianggap gagal.
+const Timeout = 2 * time.Second
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Check satu dependency yang harus sehat supaya instance boleh menerima traffic.
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// Result hasil satu check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report hasil readiness. Checks kosong kalau instance sedang draining (check tidak dijalankan).
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// OK true kalau instance siap menerima traffic.
+func (r Report) OK() bool { return r.Status == StatusOK }
+
+// Run menjalankan semua check paralel, masing-masing dengan Timeout.
+func Run(ctx context.Context, checks []Check) Report {
+	if Draining() {
+		return Report{Status: StatusDraining}
+	}
+	rep := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, Timeout)
+			defer cancel()
+			start := time.Now()
+			err := ch.Fn(cctx)
+			res := Result{Status: StatusOK, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				res.Status, res.Error = StatusFail, err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			rep.Checks[ch.Name] = res
+			if err != nil {
+				rep.Status = StatusFail
+			}
+		}()
+	}
+	wg.Wait()
+	return rep
+}
+
+var draining atomic.Bool
+
+// SetDraining menandai instance sedang (atau tidak lagi) shutdown. Selama draining readiness gagal supaya
+// load balancer berhenti mengirim request baru, sementara request yang berjalan dibiarkan selesai.
+func SetDraining(v bool) { draining.Store(v) }
+
+// Draining lihat SetDraining.
+func Draining() bool { return draining.Load() }
@@ -0,0 +1,83 @@
+// Package i18n katalog pesan API. Kunci katalog adalah teks bahasa Inggris yang dipakai di kode,
+// jadi bahasa Inggris tidak butuh tabel sendiri dan selalu menjadi fallback kalau terjemahan belum ada.
+// Pesan dengan parameter memakai format fmt (mis. "must be at least %s characters").
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	EN = "en"
+	ID = "id"
+
+	Default = EN
+)
+
+// ContextKey key gin.Context tempat middleware menyimpan bahasa hasil negosiasi.
+const ContextKey = "lang"
+
+// catalogs terjemahan per bahasa selain bahasa Inggris.
+var catalogs = map[string]map[string]string{
+	ID: id,
+}
+
+// Supported daftar bahasa yang dibundel, bahasa default lebih dulu.
+func Supported() []string { return []string{EN, ID} }
+
+// T menerjemahkan key ke bahasa lang lalu mengisi args (kalau ada). Key yang tidak ada di katalog
+// dikembalikan apa adanya (bahasa Inggris).
+func T(lang, key string, args ...interface{}) string {
+	msg := key
+	if tr, ok := catalogs[lang][key]; ok {
+		msg = tr
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Negotiate memilih bahasa dari header Accept-Language (RFC 9110) berdasarkan nilai q tertinggi.
+// Subtag region diabaikan (id-ID -> id); bahasa yang tidak didukung jatuh ke Default.
+func Negotiate(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var cands []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if base == "in" { // kode lama untuk bahasa Indonesia
+			base = ID
+		}
+		if q > 0 && supported(base) {
+			cands = append(cands, candidate{base, q})
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+	if len(cands) == 0 {
+		return Default
+	}
+	return cands[0].lang
+}
+
+func supported(lang string) bool {
+	if lang == EN {
+		return true
+	}
+	_, ok := catalogs[lang]
+	return ok
+}
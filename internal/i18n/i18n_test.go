@@ -0,0 +1,53 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                          EN,
+		"id":                        ID,
+		"id-ID,id;q=0.9,en;q=0.8":   ID,
+		"en-US,en;q=0.9,id;q=0.8":   EN,
+		"fr-FR,id;q=0.5":            ID,
+		"fr, de;q=0.9":              EN,
+		"en;q=0.4, id;q=0.7":        ID,
+		"id;q=0, en":                EN,
+		"in":                        ID,
+		"*":                         EN,
+		"id;q=bukan-angka, en;q=.5": EN,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(ID, "Lead not found"); got != "Lead tidak ditemukan" {
+		t.Errorf("T(id) = %q", got)
+	}
+	if got := T(EN, "Lead not found"); got != "Lead not found" {
+		t.Errorf("T(en) = %q", got)
+	}
+	if got := T(ID, "pesan tanpa terjemahan"); got != "pesan tanpa terjemahan" {
+		t.Errorf("fallback = %q", got)
+	}
+	if got := T(ID, "must be at least %s characters", "6"); got != "minimal 6 karakter" {
+		t.Errorf("T with args = %q", got)
+	}
+}
+
+// Terjemahan harus memakai verb fmt yang sama banyak dengan key-nya.
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, msg := range catalog {
+			if strings.Count(key, "%") != strings.Count(msg, "%") {
+				t.Errorf("%s: %q -> %q: jumlah verb berbeda", lang, key, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package i18n
+
+// id terjemahan bahasa Indonesia. Urutan mengikuti asal pesan: response umum, handler, middleware auth,
+// lalu validasi field.
+var id = map[string]string{
+	// Default response.* dan judul status HTTP (problem+json)
+	"Success":                  "Berhasil",
+	"Bad Request":              "Permintaan tidak valid",
+	"Unauthorized":             "Tidak terautentikasi",
+	"Forbidden":                "Akses ditolak",
+	"Not Found":                "Tidak ditemukan",
+	"Method Not Allowed":       "Metode tidak diizinkan",
+	"Conflict":                 "Konflik",
+	"Precondition Failed":      "Prasyarat gagal",
+	"Precondition Required":    "Prasyarat diperlukan",
+	"Unsupported Media Type":   "Tipe media tidak didukung",
+	"Unprocessable Entity":     "Data tidak dapat diproses",
+	"Request Entity Too Large": "Body request terlalu besar",
+	"Too Many Requests":        "Terlalu banyak permintaan",
+	"Internal Server Error":    "Terjadi kesalahan pada server",
+	"Validation Error":         "Validasi gagal",
+	"Internal server error":    "Terjadi kesalahan pada server",
+	"Route not found":          "Route tidak ditemukan",
+	"Method not allowed":       "Metode tidak diizinkan untuk route ini",
+
+	// Rate limit
+	"Rate limit exceeded, try again later": "Batas jumlah request terlampaui, coba lagi nanti",
+
+	// Health
+	"Alive":             "Aktif",
+	"Ready":             "Siap",
+	"Service not ready": "Layanan belum siap",
+	"Build info":        "Info build",
+
+	// Request body
+	"Request body must be valid JSON":                   "Body request harus berupa JSON yang valid",
+	"Invalid JSON":                                      "JSON tidak valid",
+	"Merge patch must be a JSON object":                 "Merge patch harus berupa objek JSON",
+	"Content-Type must be application/merge-patch+json": "Content-Type harus application/merge-patch+json",
+	"Failed to read request body":                       "Gagal membaca body request",
+	"Request body too large":                            "Body request terlalu besar",
+	"Failed to apply patch":                             "Gagal menerapkan patch",
+	"If-Match header is required":                       "Header If-Match wajib dikirim",
+	"Resource has been modified":                        "Data sudah diubah oleh pihak lain",
+	"Invalid from date, expected YYYY-MM-DD":            "Tanggal from tidak valid, format YYYY-MM-DD",
+	"Invalid to date, expected YYYY-MM-DD":              "Tanggal to tidak valid, format YYYY-MM-DD",
+	"Invalid cursor":                                    "Cursor tidak valid",
+	"Invalid query parameters":                          "Parameter query tidak valid",
+	"Cursor pagination requires sort by created_at":     "Pagination cursor hanya bisa dengan sort created_at",
+
+	// Auth
+	"User registered":                   "Registrasi berhasil",
+	"Login success":                     "Login berhasil",
+	"Logged out":                        "Berhasil logout",
+	"Profile":                           "Profil",
+	"Reset token generated (test mode)": "Token reset dibuat (mode test)",
+	"Password reset requested":          "Permintaan reset password diterima",
+	"Password updated":                  "Password berhasil diperbarui",
+	"Email not found":                   "Email tidak ditemukan",
+	"Email already registered":          "Email sudah terdaftar",
+	"Email or password is incorrect":    "Email atau password salah",
+	"Reset token invalid or expired":    "Token reset tidak valid atau sudah kedaluwarsa",
+	"Account is suspended":              "Akun sedang ditangguhkan",
+	"Account is deactivated":            "Akun sudah dinonaktifkan",
+	"Failed to create user":             "Gagal membuat user",
+	"Failed to sign token":              "Gagal membuat token",
+	"Failed to create reset token":      "Gagal membuat token reset",
+	"Failed to reset password":          "Gagal mereset password",
+	"Failed to log out":                 "Gagal logout",
+
+	// Middleware auth
+	"missing bearer token":     "Bearer token tidak dikirim",
+	"invalid or expired token": "Token tidak valid atau sudah kedaluwarsa",
+	"invalid claims":           "Claim token tidak valid",
+	"user not found":           "User tidak ditemukan",
+	"token revoked":            "Token sudah dicabut",
+	"account suspended":        "Akun sedang ditangguhkan",
+	"account deactivated":      "Akun sudah dinonaktifkan",
+	"admin only":               "Khusus admin",
+	"unauthorized":             "Tidak terautentikasi",
+	"Invalid metrics token":    "Token metrics tidak valid",
+	"CORS origin not allowed":  "Origin tidak diizinkan oleh CORS",
+
+	// Leads
+	"Lead created":                 "Lead berhasil dibuat",
+	"Lead list":                    "Daftar lead",
+	"Lead detail":                  "Detail lead",
+	"Lead updated":                 "Lead berhasil diperbarui",
+	"Lead deleted":                 "Lead berhasil dihapus",
+	"Lead restored":                "Lead berhasil dipulihkan",
+	"Lead trash":                   "Tempat sampah lead",
+	"Lead summary":                 "Ringkasan lead",
+	"Lead not found":               "Lead tidak ditemukan",
+	"Lead not found in trash":      "Lead tidak ada di tempat sampah",
+	"Failed to create lead":        "Gagal membuat lead",
+	"Failed to list leads":         "Gagal mengambil daftar lead",
+	"Failed to get lead":           "Gagal mengambil lead",
+	"Failed to update lead":        "Gagal memperbarui lead",
+	"Failed to delete lead":        "Gagal menghapus lead",
+	"Failed to restore lead":       "Gagal memulihkan lead",
+	"Failed to build lead summary": "Gagal membuat ringkasan lead",
+
+	// Projects
+	"Project created":            "Project berhasil dibuat",
+	"Project list":               "Daftar project",
+	"Project detail":             "Detail project",
+	"Project updated":            "Project berhasil diperbarui",
+	"Project deleted":            "Project berhasil dihapus",
+	"Project restored":           "Project berhasil dipulihkan",
+	"Project trash":              "Tempat sampah project",
+	"Project not found":          "Project tidak ditemukan",
+	"Project not found in trash": "Project tidak ada di tempat sampah",
+	"Failed to create project":   "Gagal membuat project",
+	"Failed to list projects":    "Gagal mengambil daftar project",
+	"Failed to get project":      "Gagal mengambil project",
+	"Failed to update project":   "Gagal memperbarui project",
+	"Failed to delete project":   "Gagal menghapus project",
+	"Failed to restore project":  "Gagal memulihkan project",
+
+	// Admin users
+	"User created":                           "User berhasil dibuat",
+	"User list":                              "Daftar user",
+	"User detail":                            "Detail user",
+	"User updated":                           "User berhasil diperbarui",
+	"User deleted":                           "User berhasil dihapus",
+	"User restored":                          "User berhasil dipulihkan",
+	"User trash":                             "Tempat sampah user",
+	"User suspended":                         "User berhasil ditangguhkan",
+	"User deactivated":                       "User berhasil dinonaktifkan",
+	"User reactivated":                       "User berhasil diaktifkan kembali",
+	"User sessions revoked":                  "Semua sesi user dicabut",
+	"Leads and projects reassigned":          "Lead dan project berhasil dialihkan",
+	"User not found":                         "User tidak ditemukan",
+	"User not found in trash":                "User tidak ada di tempat sampah",
+	"Cannot remove the last active admin":    "Admin aktif terakhir tidak boleh dihapus atau dinonaktifkan",
+	"Reassign target must be an active user": "Tujuan pengalihan harus user yang aktif",
+	"User name is not unique":                "Nama user dipakai lebih dari satu user, alihkan lead secara manual",
+	"Trash purged":                           "Tempat sampah dikosongkan",
+	"Audit log":                              "Audit log",
+	"Failed to list users":                   "Gagal mengambil daftar user",
+	"Failed to get user":                     "Gagal mengambil user",
+	"Failed to update user":                  "Gagal memperbarui user",
+	"Failed to delete user":                  "Gagal menghapus user",
+	"Failed to restore user":                 "Gagal memulihkan user",
+	"Failed to suspend user":                 "Gagal menangguhkan user",
+	"Failed to deactivate user":              "Gagal menonaktifkan user",
+	"Failed to reactivate user":              "Gagal mengaktifkan kembali user",
+	"Failed to revoke sessions":              "Gagal mencabut sesi",
+	"Failed to reassign ownership":           "Gagal mengalihkan kepemilikan",
+	"Failed to list trash":                   "Gagal mengambil isi tempat sampah",
+	"Failed to purge trash":                  "Gagal mengosongkan tempat sampah",
+	"Failed to list audit log":               "Gagal mengambil audit log",
+
+	// Validasi field (response.FieldError)
+	"is required":                     "wajib diisi",
+	"is invalid":                      "tidak valid",
+	"must be a valid email address":   "harus berupa alamat email yang valid",
+	"must be a valid URL":             "harus berupa URL yang valid",
+	"must be one of: %s":              "harus salah satu dari: %s",
+	"must be at least %s":             "minimal %s",
+	"must be at least %s character":   "minimal %s karakter",
+	"must be at least %s characters":  "minimal %s karakter",
+	"must be at least %s item":        "minimal %s item",
+	"must be at least %s items":       "minimal %s item",
+	"must be at most %s":              "maksimal %s",
+	"must be at most %s character":    "maksimal %s karakter",
+	"must be at most %s characters":   "maksimal %s karakter",
+	"must be at most %s item":         "maksimal %s item",
+	"must be at most %s items":        "maksimal %s item",
+	"must be exactly %s":              "harus tepat %s",
+	"must be exactly %s character":    "harus tepat %s karakter",
+	"must be exactly %s characters":   "harus tepat %s karakter",
+	"must be exactly %s item":         "harus tepat %s item",
+	"must be exactly %s items":        "harus tepat %s item",
+	"must be a string":                "harus berupa string",
+	"must be a boolean":               "harus berupa boolean",
+	"must be an integer":              "harus berupa bilangan bulat",
+	"must be a number":                "harus berupa angka",
+	"must be an array":                "harus berupa array",
+	"must be an object":               "harus berupa objek",
+	"is not a recognised field":       "bukan field yang dikenali",
+	"is not a filterable field":       "bukan field yang bisa difilter",
+	"does not support operator %s":    "tidak mendukung operator %s",
+	"%s is not a sortable field":      "%s bukan field yang bisa diurutkan",
+	"must be a date or RFC 3339 time": "harus berupa tanggal (YYYY-MM-DD) atau waktu RFC 3339",
+	"request body must be valid JSON": "body request harus berupa JSON yang valid",
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+)
+
+// TrashRetention durasi data disimpan di trash sebelum boleh dipurge.
+func TrashRetention(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+}
+
+// RunTrashPurger mem-purge trash secara berkala sampai ctx dibatalkan.
+// Tidak melakukan apa-apa kalau TrashPurgeInterval <= 0.
+func RunTrashPurger(ctx context.Context, cfg *config.Config, db *gorm.DB) {
+	if cfg.TrashPurgeInterval <= 0 {
+		return
+	}
+	t := time.NewTicker(time.Duration(cfg.TrashPurgeInterval) * time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			n, err := database.PurgeTrash(db, time.Now().Add(-TrashRetention(cfg)))
+			if err != nil {
+				slog.ErrorContext(ctx, "trash purge failed", "error", err)
+				continue
+			}
+			slog.InfoContext(ctx, "trash purged", "purged", n)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// SlowQueryThreshold query yang lebih lama dari ini di-log sebagai warning.
+const SlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger logger GORM yang menulis lewat slog. Logger diambil dari context query (db.WithContext),
+// jadi query dari request ikut membawa request_id.
+type GormLogger struct {
+	Level gormlogger.LogLevel
+}
+
+// NewGormLogger: error dan slow query saja; di level debug semua query ikut di-log.
+func NewGormLogger(level slog.Level) *GormLogger {
+	l := gormlogger.Warn
+	if level <= slog.LevelDebug {
+		l = gormlogger.Info
+	}
+	return &GormLogger{Level: l}
+}
+
+func (g *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &GormLogger{Level: level}
+}
+
+func (g *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if g.Level >= gormlogger.Info {
+		FromContext(ctx).InfoContext(ctx, "gorm", "detail", fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if g.Level >= gormlogger.Warn {
+		FromContext(ctx).WarnContext(ctx, "gorm", "detail", fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if g.Level >= gormlogger.Error {
+		FromContext(ctx).ErrorContext(ctx, "gorm", "detail", fmt.Sprintf(msg, args...))
+	}
+}
+
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.Level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && g.Level >= gormlogger.Error:
+		sql, rows := fc()
+		FromContext(ctx).ErrorContext(ctx, "query failed", "error", err, "sql", sql, "rows", rows, "elapsed", elapsed)
+	case elapsed > SlowQueryThreshold && g.Level >= gormlogger.Warn:
+		sql, rows := fc()
+		FromContext(ctx).WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	case g.Level >= gormlogger.Info:
+		sql, rows := fc()
+		FromContext(ctx).DebugContext(ctx, "query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
@@ -0,0 +1,57 @@
+// Package logging menyiapkan logger log/slog aplikasi: JSON di production (untuk log pipeline), teks di
+// development. Logger per request disimpan di context.Context supaya handler, service dan GORM menulis
+// log dengan field request yang sama (request_id, user_id, route).
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+// New logger sesuai cfg.AppEnv dan cfg.LogLevel. Atribut sensitif selalu disensor (lihat redact).
+func New(cfg *config.Config, w io.Writer) *slog.Logger {
+	if cfg.IsProduction() {
+		return NewJSON(w, ParseLevel(cfg.LogLevel))
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: ParseLevel(cfg.LogLevel), ReplaceAttr: redact}))
+}
+
+// NewJSON logger JSON satu objek per baris, dengan sensor atribut sensitif.
+func NewJSON(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: redact}))
+}
+
+// ParseLevel debug|info|warn|error; nilai lain dianggap info.
+func ParseLevel(s string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+type ctxKey struct{}
+
+// WithContext menyimpan l di ctx.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext logger request dari ctx, atau slog.Default() kalau tidak ada (mis. job background).
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+// With menambah field ke logger di ctx, mis. user_id setelah token diverifikasi.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+func TestNewFormatFollowsAppEnv(t *testing.T) {
+	var buf bytes.Buffer
+	New(&config.Config{AppEnv: "production"}, &buf).Info("hello", "k", "v")
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("production log is not JSON: %s", buf.String())
+	}
+
+	buf.Reset()
+	New(&config.Config{AppEnv: "development", LogLevel: "warn"}, &buf).Info("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("info logged at warn level: %s", buf.String())
+	}
+	New(&config.Config{AppEnv: "development"}, &buf).Info("hello", "k", "v")
+	if got := buf.String(); !strings.Contains(got, "msg=hello k=v") {
+		t.Fatalf("development log = %q, want text format", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSON(&buf, slog.LevelInfo)
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret-jwt")
+	h.Set("Accept", "application/json")
+	l.Info("login",
+		"password", "hunter2",
+		slog.Group("body", "new_password", "hunter3", "reset_token", "abc123", "email", "budi@test.local"),
+		"Authorization", "Bearer secret-jwt",
+		"headers", h,
+	)
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "hunter3", "abc123", "secret-jwt"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q: %s", secret, out)
+		}
+	}
+	for _, keep := range []string{"budi@test.local", "application/json"} {
+		if !strings.Contains(out, keep) {
+			t.Errorf("log lost %q: %s", keep, out)
+		}
+	}
+	if h.Get("Authorization") != "Bearer secret-jwt" {
+		t.Error("redaction modified the original header")
+	}
+}
+
+func TestContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Fatal("FromContext without logger should return slog.Default()")
+	}
+	var buf bytes.Buffer
+	ctx := WithContext(context.Background(), NewJSON(&buf, slog.LevelInfo).With("request_id", "r1"))
+	ctx = With(ctx, "user_id", 7)
+	FromContext(ctx).Info("x")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["request_id"] != "r1" || line["user_id"] != float64(7) {
+		t.Fatalf("line = %v", line)
+	}
+}
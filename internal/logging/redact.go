@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys nama atribut (case-insensitive, di grup mana pun) yang nilainya tidak boleh masuk log.
+var sensitiveKeys = map[string]bool{
+	"authorization":  true,
+	"cookie":         true,
+	"set-cookie":     true,
+	"password":       true,
+	"new_password":   true,
+	"token":          true,
+	"reset_token":    true,
+	"access_token":   true,
+	"jwt_secret":     true,
+	"admin_password": true,
+}
+
+// sensitiveHeaders header yang disensor kalau http.Header ikut di-log.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	if h, ok := a.Value.Any().(http.Header); ok {
+		return slog.Any(a.Key, RedactHeader(h))
+	}
+	return a
+}
+
+// RedactHeader salinan h dengan header sensitif disensor.
+func RedactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if out.Get(k) != "" {
+			out.Set(k, redacted)
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+const (
+	// scrapeTimeout batas waktu query agregat per scrape.
+	scrapeTimeout = 5 * time.Second
+	// businessCacheTTL lama hasil query agregat dipakai ulang, supaya beberapa scraper (atau scrape interval
+	// pendek) tidak menjalankan GROUP BY ke database di setiap scrape.
+	businessCacheTTL = 30 * time.Second
+)
+
+var (
+	leadsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "leads"),
+		"Jumlah lead aktif (tidak di trash) per source; source kosong menjadi \"unknown\", source di luar daftar dikenal menjadi \"other\".",
+		[]string{"source"}, nil)
+	dealsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "deals"),
+		"Jumlah deal aktif per stage; stage di luar daftar dikenal menjadi \"other\".", []string{"stage"}, nil)
+	dealAmountDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "deal_amount_idr"),
+		"Total amount_idr deal aktif per stage; stage di luar daftar dikenal menjadi \"other\".", []string{"stage"}, nil)
+)
+
+// knownSources nilai label source yang diizinkan. leads.source teks bebas, jadi nilai lain digabung ke
+// "other" supaya jumlah series tidak tumbuh tanpa batas.
+var knownSources = map[string]bool{
+	"cold_call": true, "email_campaign": true, "event": true, "referral": true, "social_media": true, "website": true,
+}
+
+// knownStages nilai label stage yang diizinkan; deals.stage juga teks bebas (dataset memakai "Won", "Lost", "Pending").
+var knownStages = map[string]bool{"pending": true, "won": true, "lost": true}
+
+// label menormalkan nilai teks bebas ke label: huruf kecil, spasi dan "-" menjadi "_"; "Cold Call" menjadi
+// cold_call. Nilai kosong menjadi "unknown", nilai di luar known menjadi "other".
+func label(value string, known map[string]bool) string {
+	l := strings.ToLower(strings.TrimSpace(value))
+	l = strings.NewReplacer(" ", "_", "-", "_").Replace(l)
+	switch {
+	case l == "":
+		return "unknown"
+	case known[l]:
+		return l
+	}
+	return "other"
+}
+
+// businessCollector menghitung gauge bisnis dari database (replica kalau ada) saat /metrics di-scrape.
+// Hasilnya dipakai ulang selama businessCacheTTL, jadi nilainya paling lama tertinggal sebanyak itu dari isi
+// tabel (termasuk data dari seed atau restore). Hasil yang gagal tidak disimpan.
+type businessCollector struct {
+	store repository.Store
+
+	mu      sync.Mutex
+	at      time.Time
+	metrics []prometheus.Metric
+}
+
+// RegisterBusiness menambahkan gauge bisnis dari store.
+func (m *Metrics) RegisterBusiness(store repository.Store) error {
+	return m.Registry.Register(&businessCollector{store: store})
+}
+
+func (b *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- leadsDesc
+	ch <- dealsDesc
+	ch <- dealAmountDesc
+}
+
+func (b *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	// Mutex juga membuat scrape bersamaan menunggu satu query, bukan masing-masing menjalankannya.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	metrics := b.metrics
+	if metrics == nil || time.Since(b.at) >= businessCacheTTL {
+		var ok bool
+		if metrics, ok = b.query(); ok {
+			b.metrics, b.at = metrics, time.Now()
+		}
+	}
+	for _, m := range metrics {
+		ch <- m
+	}
+}
+
+// query menjalankan agregat ke database. ok false kalau salah satu query gagal; metrik yang gagal
+// dikirim sebagai invalid metric supaya scrape-nya ikut gagal.
+func (b *businessCollector) query() (out []prometheus.Metric, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+	ok = true
+
+	bySource, err := b.store.Leads().CountBy(ctx, "source", repository.DateRange{})
+	if err != nil {
+		slog.ErrorContext(ctx, "metrics: count leads failed", "error", err)
+		out, ok = append(out, prometheus.NewInvalidMetric(leadsDesc, err)), false
+	}
+	bySourceLabel := map[string]int64{}
+	for source, n := range bySource {
+		bySourceLabel[label(source, knownSources)] += n
+	}
+	for l, n := range bySourceLabel {
+		out = append(out, prometheus.MustNewConstMetric(leadsDesc, prometheus.GaugeValue, float64(n), l))
+	}
+
+	st, err := b.store.Deals().Stats(ctx, repository.DateRange{})
+	if err != nil {
+		slog.ErrorContext(ctx, "metrics: deal stats failed", "error", err)
+		return append(out, prometheus.NewInvalidMetric(dealsDesc, err)), false
+	}
+	count, amount := map[string]int64{}, map[string]int64{}
+	for stage, n := range st.ByStage {
+		l := label(stage, knownStages)
+		count[l] += n
+		amount[l] += st.AmountByStage[stage]
+	}
+	for l, n := range count {
+		out = append(out,
+			prometheus.MustNewConstMetric(dealsDesc, prometheus.GaugeValue, float64(n), l),
+			prometheus.MustNewConstMetric(dealAmountDesc, prometheus.GaugeValue, float64(amount[l]), l))
+	}
+	return out, ok
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// InstrumentDB memasang callback GORM untuk durasi query dan collector sql.DBStats (pool: open, in use,
+// idle, wait count/duration) dengan label db_name=name.
+func (m *Metrics) InstrumentDB(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := m.Registry.Register(collectors.NewDBStatsCollector(sqlDB, name)); err != nil {
+		return err
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		cb.Create().After("gorm:create").Register("metrics:after_create", m.observe("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		cb.Query().After("gorm:query").Register("metrics:after_query", m.observe("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		cb.Update().After("gorm:update").Register("metrics:after_update", m.observe("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", m.observe("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		cb.Row().After("gorm:row").Register("metrics:after_row", m.observe("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", m.observe("raw")),
+	)
+}
+
+func startQuery(tx *gorm.DB) { tx.InstanceSet(startKey, time.Now()) }
+
+func (m *Metrics) observe(op string) func(*gorm.DB) {
+	return func(tx *gorm.DB) { m.observeQuery(tx, op) }
+}
+
+func (m *Metrics) observeQuery(tx *gorm.DB, op string) {
+	v, ok := tx.InstanceGet(startKey)
+	if !ok {
+		return
+	}
+	start, _ := v.(time.Time)
+	table := tx.Statement.Table
+	if table == "" {
+		table = "unknown"
+	}
+	m.queries.WithLabelValues(op, table).Observe(time.Since(start).Seconds())
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		m.dbErrors.WithLabelValues(op, table).Inc()
+	}
+}
@@ -0,0 +1,108 @@
+// Package metrics metrik Prometheus untuk /metrics: HTTP per route template, durasi query GORM, pool
+// sql.DBStats dan gauge bisnis (lead per source, deal per stage). Setiap Metrics punya registry sendiri,
+// jadi beberapa router (mis. di test) tidak saling bentrok.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+const namespace = "godigi"
+
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+	queries  *prometheus.HistogramVec
+	dbErrors *prometheus.CounterVec
+}
+
+// New registry baru berisi collector Go runtime, process dan metrik HTTP/DB aplikasi.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_total",
+			Help: "Jumlah request HTTP per method, route template dan status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+			Help:    "Latency request HTTP per method, route template dan status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_in_flight",
+			Help: "Request HTTP yang sedang diproses.",
+		}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "db", Name: "query_duration_seconds",
+			Help:    "Durasi query GORM per operasi dan tabel.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "db", Name: "query_errors_total",
+			Help: "Query GORM yang gagal (record not found tidak dihitung).",
+		}, []string{"operation", "table"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.inFlight, m.queries, m.dbErrors,
+	)
+	return m
+}
+
+// Middleware mencatat jumlah dan latency request. Label route memakai template (/leads/:id), bukan path
+// asli, supaya cardinality tetap kecil; request ke route yang tidak ada dicatat sebagai "unmatched".
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.duration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler endpoint format exposition Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// RequireToken melindungi /metrics dengan `Authorization: Bearer <token>`. Token kosong berarti terbuka.
+func RequireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			response.Unauthorized(c, "Invalid metrics token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
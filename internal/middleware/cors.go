@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+const (
+	corsAllowMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders = "Authorization, Content-Type, Accept, Accept-Language, If-Match, X-Request-ID"
+	// Response headers the SPA needs to read besides the CORS-safelisted ones
+	corsExposeHeaders = "X-Request-ID, ETag, Location, Content-Language, Retry-After, " +
+		"RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy"
+)
+
+// CORS returns a middleware that lets the configured browser origins call the API. Preflight requests
+// from an allowed origin are answered with 204 here, before auth and rate limiting; preflights from other
+// origins get 403. Without allowed origins the middleware does nothing (same-origin only)
+func CORS(cfg config.CORS) gin.HandlerFunc {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	origins := make([]string, len(cfg.AllowedOrigins))
+	for i, o := range cfg.AllowedOrigins {
+		origins[i] = normalizeOrigin(o)
+	}
+	anyOrigin := slices.Contains(origins, "*")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		allowed := anyOrigin || slices.Contains(origins, normalizeOrigin(origin))
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !allowed {
+			if preflight {
+				response.Fail(c, http.StatusForbidden, response.CodeForbidden, "CORS origin not allowed", nil)
+				c.Abort()
+				return
+			}
+			// A simple request still runs; the browser hides the response without the CORS headers
+			c.Next()
+			return
+		}
+
+		if anyOrigin && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !preflight {
+			h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			c.Next()
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+		h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// normalizeOrigin makes configured and requested origins comparable: scheme and host are
+// case-insensitive and a trailing slash in the config is tolerated
+func normalizeOrigin(o string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(o), "/"))
+}
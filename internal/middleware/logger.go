@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
+)
+
+// Logger returns a middleware that puts a request-scoped logger (request_id, method, route) into the
+// request context and writes one access log line per request. Must run after RequestID and
+// tracing.Middleware so trace_id/span_id are included
+func Logger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		l := base.With("request_id", c.GetString(RequestIDKey), "method", c.Request.Method, "route", route)
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			l = l.With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+		}
+		c.Request = c.Request.WithContext(logging.WithContext(c.Request.Context(), l))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []any{
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"path", c.Request.URL.Path,
+			"ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		// c.Request may have been replaced downstream (auth adds user_id to the logger)
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).Log(ctx, level, "request", attrs...)
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
@@ -9,6 +11,14 @@ import (
 // NotFoundHandler returns a middleware that handles 404 errors with JSON response
 func NotFoundHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		response.NotFound(c, "Route not found")
+		response.Fail(c, http.StatusNotFound, response.CodeRouteNotFound, "Route not found", nil)
+	}
+}
+
+// MethodNotAllowedHandler returns a middleware that handles 405 errors with JSON response.
+// Gin already sets the Allow header before this handler runs
+func MethodNotAllowedHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response.Fail(c, http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, "Method not allowed", nil)
 	}
-}
\ No newline at end of file
+}
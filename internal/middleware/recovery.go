@@ -1,21 +1,65 @@
 package middleware
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/oktaharis/uji-teknis-godigi/internal/logging"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
 )
 
-// JSONRecovery returns a middleware that recovers from panics and returns JSON error response
+// JSONRecovery returns a middleware that recovers from panics, logs the stack trace with the
+// request ID and returns the JSON error envelope
 func JSONRecovery() gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(string); ok {
-			log.Printf("Panic recovered: %s", err)
-		} else {
-			log.Printf("Panic recovered: %v", recovered)
-		}
-		response.InternalError(c, "Internal server error")
-	})
-}
\ No newline at end of file
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if brokenPipe(recovered) {
+				// the client is gone, nothing can be written
+				logging.FromContext(c.Request.Context()).Warn("connection closed", "error", recovered)
+				c.Abort()
+				return
+			}
+			logging.FromContext(c.Request.Context()).Error("panic recovered",
+				"panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.InternalError(c, "Internal server error")
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+func brokenPipe(recovered interface{}) bool {
+	if recovered == http.ErrAbortHandler {
+		return true
+	}
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr, &sysErr) {
+		return errors.Is(sysErr.Err, syscall.EPIPE) || errors.Is(sysErr.Err, syscall.ECONNRESET)
+	}
+	msg := strings.ToLower(opErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
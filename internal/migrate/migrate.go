@@ -0,0 +1,484 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var embedded embed.FS
+
+// Dir lokasi file migration di source tree (satu subfolder per dialect), dipakai oleh `migrate create`.
+const Dir = "internal/migrate/migrations"
+
+// Dialects yang punya folder migration sendiri. Nomor versi harus sama di semua folder.
+var Dialects = []string{"mysql", "postgres", "sqlite"}
+
+const (
+	lockName    = "godigi_schema_migrations"
+	lockKey     = 7243001 // key pg_advisory_lock
+	lockTimeout = 60      // detik
+)
+
+var fileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var ErrLocked = errors.New("another instance is running migrations")
+
+// ErrBaseline Down sampai ke baseline. Baseline meng-adopt tabel yang sudah ada, jadi rollback-nya berarti
+// menghapus data production.
+var ErrBaseline = errors.New("baseline migration cannot be rolled back")
+
+// baselineVersion versi migration yang membuat (atau meng-adopt) skema awal.
+const baselineVersion = 1
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// pending, applied, missing (tercatat di DB tapi file-nya tidak ada), checksum_mismatch
+	State string `json:"state"`
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// New memuat migration yang di-embed ke binary untuk dialect (mysql, postgres, sqlite).
+func New(db *sql.DB, dialect string) (*Migrator, error) {
+	if !isDialect(dialect) {
+		return nil, fmt.Errorf("unsupported migration dialect %q", dialect)
+	}
+	sub, err := fs.Sub(embedded, "migrations/"+dialect)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: ms}, nil
+}
+
+func isDialect(d string) bool {
+	for _, x := range Dialects {
+		if x == d {
+			return true
+		}
+	}
+	return false
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		v, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[v]
+		if !ok {
+			mig = &Migration{Version: v, Name: m[2]}
+			byVersion[v] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %04d has conflicting names %q and %q", v, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+			sum := sha256.Sum256(body)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(body)
+		}
+	}
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+type applied struct {
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock. Lock MySQL dan PostgreSQL
+// berlaku per koneksi, jadi semua statement harus lewat conn. SQLite cukup mengandalkan lock file-nya.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.dialect {
+	case "mysql":
+		var got sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&got); err != nil {
+			return err
+		}
+		if !got.Valid || got.Int64 != 1 {
+			return ErrLocked
+		}
+		defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName) //nolint:errcheck
+	case "postgres":
+		lctx, cancel := context.WithTimeout(ctx, lockTimeout*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(lctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			if errors.Is(lctx.Err(), context.DeadlineExceeded) {
+				return ErrLocked
+			}
+			return err
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey) //nolint:errcheck
+	}
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+type queryer interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, q queryer) error {
+	ddl := `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version BIGINT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  checksum CHAR(64) NOT NULL,
+  applied_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (version)
+)`
+	if m.dialect == "mysql" {
+		ddl += " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	}
+	_, err := q.ExecContext(ctx, ddl)
+	return err
+}
+
+// tableExists true kalau schema_migrations sudah dibuat.
+func (m *Migrator) tableExists(ctx context.Context) (bool, error) {
+	query := "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'"
+	switch m.dialect {
+	case "postgres":
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'"
+	case "sqlite":
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"
+	}
+	var n int
+	err := m.db.QueryRowContext(ctx, query).Scan(&n)
+	return n > 0, err
+}
+
+// rebind mengganti placeholder ? menjadi $1, $2, ... untuk PostgreSQL.
+func (m *Migrator) rebind(query string) string {
+	if m.dialect != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func readApplied(ctx context.Context, q queryer) (map[int64]applied, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]applied{}
+	for rows.Next() {
+		var v int64
+		var a applied
+		if err := rows.Scan(&v, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		out[v] = a
+	}
+	return out, rows.Err()
+}
+
+// verify memastikan file migration yang sudah dijalankan tidak diubah sesudahnya.
+func (m *Migrator) verify(done map[int64]applied) error {
+	for _, mig := range m.migrations {
+		if a, ok := done[mig.Version]; ok && a.Checksum != mig.Checksum {
+			return fmt.Errorf("checksum mismatch for migration %04d_%s: applied file was modified", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+// Up menjalankan semua migration yang belum dijalankan, berurutan.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := m.step(ctx, conn, mig.Up,
+				"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+				mig.Version, mig.Name, mig.Checksum, time.Now().UTC()); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			ran = append(ran, mig)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down me-rollback `steps` migration terakhir yang sudah dijalankan. Baseline tidak pernah di-rollback:
+// kalau steps mencapainya, Down berhenti dengan ErrBaseline setelah migration di atasnya.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			if mig.Version == baselineVersion {
+				return ErrBaseline
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %04d_%s has no down file", mig.Version, mig.Name)
+			}
+			if err := m.step(ctx, conn, mig.Down, "DELETE FROM schema_migrations WHERE version = ?", mig.Version); err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			ran = append(ran, mig)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// step menjalankan script satu migration lalu record (INSERT/DELETE schema_migrations). PostgreSQL dan
+// SQLite mendukung DDL di dalam transaksi, jadi keduanya dibungkus satu transaksi: migration yang gagal di
+// tengah tidak meninggalkan skema setengah jadi atau versi yang tercatat tanpa skemanya. MySQL tidak ikut
+// karena setiap DDL melakukan implicit commit, transaksi di sana tidak memberi jaminan apa pun.
+func (m *Migrator) step(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	if m.dialect == "mysql" {
+		if err := execScript(ctx, conn, script); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, m.rebind(record), args...)
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+	if err := execScript(ctx, tx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, m.rebind(record), args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Status membandingkan file migration dengan isi schema_migrations. Tidak mengambil lock.
+// Hanya membaca (tabel schema_migrations tidak dibuat kalau belum ada), jadi aman dipanggil dari probe readiness.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	done := map[int64]applied{}
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if done, err = readApplied(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+	var out []Status
+	known := map[int64]bool{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		st := Status{Version: mig.Version, Name: mig.Name, State: "pending"}
+		if a, ok := done[mig.Version]; ok {
+			at := a.AppliedAt
+			st.AppliedAt = &at
+			st.State = "applied"
+			if a.Checksum != mig.Checksum {
+				st.State = "checksum_mismatch"
+			}
+		}
+		out = append(out, st)
+	}
+	for v, a := range done {
+		if !known[v] {
+			at := a.AppliedAt
+			out = append(out, Status{Version: v, Name: a.Name, AppliedAt: &at, State: "missing"})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// execScript menjalankan script statement per statement (lihat splitStatements), jadi DSN tidak perlu
+// multiStatements=true. Semua jalan di koneksi (atau transaksi) yang sama supaya variabel @session tetap ada.
+func execScript(ctx context.Context, q queryer, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := q.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%w\n-- statement:\n%s", err, stmt)
+		}
+	}
+	return nil
+}
+
+// splitStatements memisah script di `;` yang berada di luar string ('...', "...", `...`) dan komentar
+// (-- sampai akhir baris, /* ... */). Quote di dalam string ditulis dua kali; escape backslash tidak
+// dikenali karena artinya beda per dialect. Komentar sebelum statement dibuang, potongan yang hanya berisi
+// komentar atau spasi dilewati.
+func splitStatements(script string) []string {
+	var out []string
+	start := -1 // posisi karakter pertama statement yang bukan komentar/spasi
+	flush := func(end int) {
+		if start >= 0 {
+			out = append(out, strings.TrimSpace(script[start:end]))
+		}
+		start = -1
+	}
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case strings.HasPrefix(script[i:], "--"):
+			i = skipUntil(script, i+2, "\n") - 1
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipUntil(script, i+2, "*/") - 1
+		case c == ';':
+			flush(i)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			if start < 0 {
+				start = i
+			}
+			if c == '\'' || c == '"' || c == '`' {
+				i = skipQuoted(script, i)
+			}
+		}
+	}
+	flush(len(script))
+	return out
+}
+
+// skipUntil posisi tepat setelah end pertama mulai dari i, atau len(s) kalau tidak ketemu.
+func skipUntil(s string, i int, end string) int {
+	if n := strings.Index(s[i:], end); n >= 0 {
+		return i + n + len(end)
+	}
+	return len(s)
+}
+
+// skipQuoted posisi quote penutup dari string yang dibuka di s[i]; quote dobel dianggap isi string.
+func skipQuoted(s string, i int) int {
+	q := s[i]
+	for i++; i < len(s); i++ {
+		if s[i] != q {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == q {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
+
+// Create membuat pasangan file up/down kosong dengan nomor versi berikutnya di setiap folder dialect,
+// supaya nomor versi tetap sinkron. Return path file yang dibuat.
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q, use [a-z0-9_]", name)
+	}
+	var next int64 = 1
+	for _, d := range Dialects {
+		existing, err := load(os.DirFS(filepath.Join(dir, d)))
+		if err != nil {
+			return nil, err
+		}
+		if n := len(existing); n > 0 && existing[n-1].Version >= next {
+			next = existing[n-1].Version + 1
+		}
+	}
+	base := fmt.Sprintf("%04d_%s", next, name)
+	var created []string
+	for _, d := range Dialects {
+		for _, kind := range []string{"up", "down"} {
+			path := filepath.Join(dir, d, base+"."+kind+".sql")
+			if err := os.WriteFile(path, []byte("-- "+base+" "+kind+" ("+d+")\n"), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, path)
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,224 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	gdb, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db, err := gdb.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newMigrator(t *testing.T) (*Migrator, *sql.DB) {
+	t.Helper()
+	db := openSQLite(t)
+	m, err := New(db, "sqlite")
+	if err != nil {
+		t.Fatalf("new migrator: %v", err)
+	}
+	return m, db
+}
+
+func versions(ms []Migration) []int64 {
+	var out []int64
+	for _, m := range ms {
+		out = append(out, m.Version)
+	}
+	return out
+}
+
+func states(t *testing.T, m *Migrator) map[int64]string {
+	t.Helper()
+	sts, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	out := map[int64]string{}
+	for _, st := range sts {
+		out[st.Version] = st.State
+	}
+	return out
+}
+
+func TestUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newMigrator(t)
+	all := versions(m.migrations)
+	last := all[len(all)-1]
+
+	ran, err := m.Up(ctx)
+	if err != nil || !slices.Equal(versions(ran), all) {
+		t.Fatalf("up = %v, %v; want %v", versions(ran), err, all)
+	}
+	if ran, err := m.Up(ctx); err != nil || len(ran) != 0 {
+		t.Fatalf("second up = %v, %v; want nothing to do", versions(ran), err)
+	}
+
+	// Down berjalan dari versi tertinggi ke bawah.
+	ran, err = m.Down(ctx, 2)
+	if err != nil || !slices.Equal(versions(ran), []int64{last, last - 1}) {
+		t.Fatalf("down 2 = %v, %v; want [%d %d]", versions(ran), err, last, last-1)
+	}
+	st := states(t, m)
+	if st[last] != "pending" || st[last-1] != "pending" || st[last-2] != "applied" {
+		t.Fatalf("status after down 2 = %v", st)
+	}
+
+	// Baseline tidak pernah di-rollback; migration di atasnya tetap di-rollback lebih dulu.
+	var above []int64
+	for v := last - 2; v > baselineVersion; v-- {
+		above = append(above, v)
+	}
+	ran, err = m.Down(ctx, len(all))
+	if !errors.Is(err, ErrBaseline) || !slices.Equal(versions(ran), above) {
+		t.Fatalf("down all = %v, %v; want versions above baseline then ErrBaseline", versions(ran), err)
+	}
+	if st := states(t, m); st[1] != "applied" || st[2] != "pending" {
+		t.Fatalf("status after down all = %v", st)
+	}
+
+	if ran, err := m.Up(ctx); err != nil || len(ran) != len(all)-1 {
+		t.Fatalf("up after down = %v, %v", versions(ran), err)
+	}
+}
+
+func TestChecksumAndMissing(t *testing.T) {
+	ctx := context.Background()
+	m, db := newMigrator(t)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE schema_migrations SET checksum = 'edited' WHERE version = 2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (99, 'gone', 'x', CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatal(err)
+	}
+
+	st := states(t, m)
+	if st[1] != "applied" || st[2] != "checksum_mismatch" || st[99] != "missing" {
+		t.Fatalf("status = %v", st)
+	}
+	for name, run := range map[string]func() ([]Migration, error){
+		"up":   func() ([]Migration, error) { return m.Up(ctx) },
+		"down": func() ([]Migration, error) { return m.Down(ctx, 1) },
+	} {
+		if _, err := run(); err == nil || !strings.Contains(err.Error(), "checksum mismatch for migration 0002") {
+			t.Errorf("%s with edited migration: err = %v, want checksum mismatch", name, err)
+		}
+	}
+}
+
+// Status dipakai probe readiness: database baru tidak boleh ikut dibuatkan tabel schema_migrations.
+func TestStatusReadOnly(t *testing.T) {
+	m, db := newMigrator(t)
+	st := states(t, m)
+	if len(st) != len(m.migrations) || st[1] != "pending" {
+		t.Fatalf("status on empty database = %v, want all pending", st)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'").Scan(&n); err != nil || n != 0 {
+		t.Fatalf("status created schema_migrations (n=%d, err=%v)", n, err)
+	}
+}
+
+// Di SQLite migration dan pencatatannya satu transaksi: yang gagal di tengah tidak meninggalkan apa pun.
+func TestFailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m := &Migrator{db: db, dialect: "sqlite", migrations: []Migration{
+		{Version: 1, Name: "broken", Up: "CREATE TABLE half (id INTEGER);\nINSERT INTO nope VALUES (1);", Checksum: "x"},
+	}}
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "migration 0001_broken up") {
+		t.Fatalf("up err = %v, want failure of 0001_broken", err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'half'").Scan(&n); err != nil || n != 0 {
+		t.Fatalf("table from failed migration exists (n=%d, err=%v)", n, err)
+	}
+	if st := states(t, m); st[1] != "pending" {
+		t.Fatalf("failed migration state = %q, want pending", st[1])
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	existing := map[string][]string{
+		"mysql":    {"0001_a.up.sql", "0003_c.up.sql"},
+		"postgres": {"0001_a.up.sql", "0002_b.up.sql"},
+		"sqlite":   {},
+	}
+	for d, files := range existing {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			if err := os.WriteFile(filepath.Join(dir, d, f), []byte("SELECT 1;"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	created, err := Create(dir, "Add Lead Score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, d := range Dialects {
+		for _, kind := range []string{"up", "down"} {
+			want = append(want, filepath.Join(dir, d, "0004_add_lead_score."+kind+".sql"))
+		}
+	}
+	if !slices.Equal(created, want) {
+		t.Fatalf("created = %v, want %v", created, want)
+	}
+	if _, err := Create(dir, "drop; table"); err == nil {
+		t.Fatal("invalid name accepted")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"one per line", "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n", []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"}},
+		{"same line", "SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"no trailing semicolon", "SELECT 1;\nSELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"multi line", "CREATE TABLE a (\n  id INT\n);", []string{"CREATE TABLE a (\n  id INT\n)"}},
+		{"semicolon in string", "INSERT INTO t VALUES ('a;b');", []string{"INSERT INTO t VALUES ('a;b')"}},
+		{"string ends line with semicolon", "INSERT INTO t VALUES ('a;\nb');", []string{"INSERT INTO t VALUES ('a;\nb')"}},
+		{"doubled quote", "INSERT INTO t VALUES ('it''s;');", []string{"INSERT INTO t VALUES ('it''s;')"}},
+		{"quoted identifier", "SELECT \"a;b\", `c;d` FROM t;", []string{"SELECT \"a;b\", `c;d` FROM t"}},
+		{"line comment", "-- pakai ; di komentar;\nSELECT 1; -- akhir;\n", []string{"SELECT 1"}},
+		{"comment inside statement", "SELECT 1 -- satu;\n, 2;", []string{"SELECT 1 -- satu;\n, 2"}},
+		{"block comment", "/* a; b */ SELECT 1 /* ; */;", []string{"SELECT 1 /* ; */"}},
+		{"dash in string", "SELECT '--;';", []string{"SELECT '--;'"}},
+		{"only comments", "-- kosong\n/* juga; kosong */\n", nil},
+		{"empty statements", ";;\n;", nil},
+	} {
+		if got := splitStatements(tc.script); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
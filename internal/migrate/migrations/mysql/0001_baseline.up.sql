@@ -0,0 +1,68 @@
+-- Skema awal, sama dengan hasil EnsureCompanyTables + AutoMigrate versi lama.
+-- IF NOT EXISTS supaya database yang sudah berjalan bisa langsung di-adopt.
+
+CREATE TABLE IF NOT EXISTS leads (
+  lead_id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  company_name VARCHAR(255) NOT NULL,
+  contact_name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  phone VARCHAR(30),
+  source VARCHAR(50),
+  industry VARCHAR(50),
+  region VARCHAR(50),
+  sales_rep VARCHAR(50),
+  status VARCHAR(30),
+  notes TEXT,
+  PRIMARY KEY (lead_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS deals (
+  deal_id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  lead_id BIGINT UNSIGNED NOT NULL,
+  deal_name VARCHAR(120),
+  amount_idr BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  term_months INT NOT NULL DEFAULT 12,
+  stage VARCHAR(20) NOT NULL,
+  closed_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (deal_id),
+  CONSTRAINT fk_deals_lead FOREIGN KEY (lead_id)
+    REFERENCES leads(lead_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS users (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL DEFAULT 'user',
+  token_version BIGINT NOT NULL DEFAULT 0,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uni_users_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS password_resets (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL,
+  token VARCHAR(128) NOT NULL,
+  expires_at DATETIME(3) NOT NULL,
+  used_at DATETIME(3) NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS projects (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  name VARCHAR(120) NOT NULL,
+  description LONGTEXT,
+  status VARCHAR(20) NOT NULL DEFAULT 'planned',
+  start_date DATETIME(3) NULL,
+  end_date DATETIME(3) NULL,
+  owner_user_id BIGINT UNSIGNED NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+-- MySQL belum punya ADD COLUMN IF NOT EXISTS; kolom bisa saja sudah dibuat AutoMigrate lama.
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'status') = 0,
+  'ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT ''active''',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
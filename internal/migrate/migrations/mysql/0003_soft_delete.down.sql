@@ -0,0 +1,8 @@
+DROP INDEX idx_users_deleted_at ON users;
+ALTER TABLE users DROP COLUMN deleted_at;
+DROP INDEX idx_projects_deleted_at ON projects;
+ALTER TABLE projects DROP COLUMN deleted_at;
+DROP INDEX idx_deals_deleted_at ON deals;
+ALTER TABLE deals DROP COLUMN deleted_at;
+DROP INDEX idx_leads_deleted_at ON leads;
+ALTER TABLE leads DROP COLUMN deleted_at;
@@ -0,0 +1,61 @@
+-- Soft delete. Kolom/index dicek dulu karena bisa sudah dibuat bootstrap lama (ensureColumn/AutoMigrate).
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'leads' AND column_name = 'deleted_at') = 0,
+  'ALTER TABLE leads ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'leads' AND index_name = 'idx_leads_deleted_at') = 0,
+  'CREATE INDEX idx_leads_deleted_at ON leads (deleted_at)',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'deals' AND column_name = 'deleted_at') = 0,
+  'ALTER TABLE deals ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'deals' AND index_name = 'idx_deals_deleted_at') = 0,
+  'CREATE INDEX idx_deals_deleted_at ON deals (deleted_at)',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'projects' AND column_name = 'deleted_at') = 0,
+  'ALTER TABLE projects ADD COLUMN deleted_at DATETIME(3) NULL',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'projects' AND index_name = 'idx_projects_deleted_at') = 0,
+  'CREATE INDEX idx_projects_deleted_at ON projects (deleted_at)',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'deleted_at') = 0,
+  'ALTER TABLE users ADD COLUMN deleted_at DATETIME(3) NULL',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'users' AND index_name = 'idx_users_deleted_at') = 0,
+  'CREATE INDEX idx_users_deleted_at ON users (deleted_at)',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  created_at DATETIME(3) NULL,
+  actor_user_id BIGINT UNSIGNED NULL,
+  actor_email VARCHAR(255),
+  action VARCHAR(30) NOT NULL,
+  entity_type VARCHAR(30) NOT NULL,
+  entity_id VARCHAR(64),
+  ip VARCHAR(64),
+  request_id VARCHAR(64),
+  changes TEXT,
+  PRIMARY KEY (id),
+  KEY idx_audit_log_created_at (created_at),
+  KEY idx_audit_log_actor_user_id (actor_user_id),
+  KEY idx_audit_log_action (action),
+  KEY idx_audit_entity (entity_type, entity_id),
+  KEY idx_audit_log_request_id (request_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
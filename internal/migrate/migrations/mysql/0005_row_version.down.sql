@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN version;
+ALTER TABLE projects DROP COLUMN version;
+ALTER TABLE leads DROP COLUMN version;
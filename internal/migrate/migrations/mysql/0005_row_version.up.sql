@@ -0,0 +1,25 @@
+-- Kolom version untuk optimistic locking (ETag / If-Match).
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'leads' AND column_name = 'version') = 0,
+  'ALTER TABLE leads ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'projects' AND column_name = 'version') = 0,
+  'ALTER TABLE projects ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'version') = 0,
+  'ALTER TABLE users ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,3 @@
+DROP INDEX idx_users_created_at_id ON users;
+DROP INDEX idx_projects_created_at_id ON projects;
+DROP INDEX idx_leads_created_at_id ON leads;
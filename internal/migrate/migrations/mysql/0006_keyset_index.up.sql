@@ -0,0 +1,5 @@
+-- Index untuk pagination keyset list (created_at DESC, id DESC).
+
+CREATE INDEX idx_leads_created_at_id ON leads (created_at, lead_id);
+CREATE INDEX idx_projects_created_at_id ON projects (created_at, id);
+CREATE INDEX idx_users_created_at_id ON users (created_at, id);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+  DROP INDEX uni_users_email,
+  DROP COLUMN active_email,
+  ADD UNIQUE KEY uni_users_email (email);
@@ -0,0 +1,7 @@
+-- Email unik hanya di antara user yang tidak di trash, supaya email user yang dihapus bisa dipakai lagi.
+-- MySQL tidak punya partial index: unique index dipasang di kolom generated yang NULL untuk user di trash.
+
+ALTER TABLE users
+  ADD COLUMN active_email VARCHAR(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL, email, NULL)) VIRTUAL,
+  DROP INDEX uni_users_email,
+  ADD UNIQUE KEY uni_users_email (active_email);
@@ -0,0 +1,2 @@
+-- Baseline tidak bisa di-rollback: tabelnya bisa berisi data production yang di-adopt (CREATE TABLE IF NOT
+-- EXISTS), jadi Migrator.Down berhenti di versi ini. Hapus tabel manual kalau memang perlu.
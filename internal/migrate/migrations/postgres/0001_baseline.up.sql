@@ -0,0 +1,62 @@
+-- Skema awal (versi PostgreSQL dari migrations/mysql/0001_baseline).
+
+CREATE TABLE IF NOT EXISTS leads (
+  lead_id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  company_name VARCHAR(255) NOT NULL,
+  contact_name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  phone VARCHAR(30),
+  source VARCHAR(50),
+  industry VARCHAR(50),
+  region VARCHAR(50),
+  sales_rep VARCHAR(50),
+  status VARCHAR(30),
+  notes TEXT
+);
+
+CREATE TABLE IF NOT EXISTS deals (
+  deal_id BIGSERIAL PRIMARY KEY,
+  lead_id BIGINT NOT NULL,
+  deal_name VARCHAR(120),
+  amount_idr BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  term_months INT NOT NULL DEFAULT 12,
+  stage VARCHAR(20) NOT NULL,
+  closed_at TIMESTAMPTZ NOT NULL,
+  CONSTRAINT fk_deals_lead FOREIGN KEY (lead_id)
+    REFERENCES leads(lead_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS users (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL DEFAULT 'user',
+  token_version BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NULL,
+  updated_at TIMESTAMPTZ NULL,
+  CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS password_resets (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  token VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NULL
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(120) NOT NULL,
+  description TEXT,
+  status VARCHAR(20) NOT NULL DEFAULT 'planned',
+  start_date TIMESTAMPTZ NULL,
+  end_date TIMESTAMPTZ NULL,
+  owner_user_id BIGINT NULL,
+  created_at TIMESTAMPTZ NULL,
+  updated_at TIMESTAMPTZ NULL
+);
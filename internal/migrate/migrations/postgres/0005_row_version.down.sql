@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE projects DROP COLUMN IF EXISTS version;
+ALTER TABLE leads DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uni_users_email;
+ALTER TABLE users ADD CONSTRAINT uni_users_email UNIQUE (email);
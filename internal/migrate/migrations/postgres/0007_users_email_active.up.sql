@@ -0,0 +1,4 @@
+-- Email unik hanya di antara user yang tidak di trash, supaya email user yang dihapus bisa dipakai lagi.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS uni_users_email ON users (email) WHERE deleted_at IS NULL;
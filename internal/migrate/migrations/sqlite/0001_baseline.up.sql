@@ -0,0 +1,60 @@
+-- Skema awal (versi SQLite dari migrations/mysql/0001_baseline).
+
+CREATE TABLE IF NOT EXISTS leads (
+  lead_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  company_name VARCHAR(255) NOT NULL,
+  contact_name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  phone VARCHAR(30),
+  source VARCHAR(50),
+  industry VARCHAR(50),
+  region VARCHAR(50),
+  sales_rep VARCHAR(50),
+  status VARCHAR(30),
+  notes TEXT
+);
+
+CREATE TABLE IF NOT EXISTS deals (
+  deal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  lead_id INTEGER NOT NULL REFERENCES leads(lead_id) ON DELETE CASCADE,
+  deal_name VARCHAR(120),
+  amount_idr BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  term_months INT NOT NULL DEFAULT 12,
+  stage VARCHAR(20) NOT NULL,
+  closed_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL DEFAULT 'user',
+  token_version BIGINT NOT NULL DEFAULT 0,
+  created_at DATETIME NULL,
+  updated_at DATETIME NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uni_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS password_resets (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  token VARCHAR(128) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  used_at DATETIME NULL,
+  created_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(120) NOT NULL,
+  description TEXT,
+  status VARCHAR(20) NOT NULL DEFAULT 'planned',
+  start_date DATETIME NULL,
+  end_date DATETIME NULL,
+  owner_user_id INTEGER NULL,
+  created_at DATETIME NULL,
+  updated_at DATETIME NULL
+);
@@ -0,0 +1,2 @@
+-- SQLite tidak punya ADD COLUMN IF NOT EXISTS; database SQLite selalu dibuat lewat migration ini.
+ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+ALTER TABLE projects DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_deals_deleted_at;
+ALTER TABLE deals DROP COLUMN deleted_at;
+DROP INDEX IF EXISTS idx_leads_deleted_at;
+ALTER TABLE leads DROP COLUMN deleted_at;
@@ -0,0 +1,13 @@
+-- Soft delete.
+
+ALTER TABLE leads ADD COLUMN deleted_at DATETIME NULL;
+CREATE INDEX IF NOT EXISTS idx_leads_deleted_at ON leads (deleted_at);
+
+ALTER TABLE deals ADD COLUMN deleted_at DATETIME NULL;
+CREATE INDEX IF NOT EXISTS idx_deals_deleted_at ON deals (deleted_at);
+
+ALTER TABLE projects ADD COLUMN deleted_at DATETIME NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
+
+ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
@@ -0,0 +1,5 @@
+-- Kolom version untuk optimistic locking (ETag / If-Match).
+
+ALTER TABLE leads ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE projects ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
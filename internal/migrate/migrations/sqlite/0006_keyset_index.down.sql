@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_projects_created_at_id;
+DROP INDEX IF EXISTS idx_leads_created_at_id;
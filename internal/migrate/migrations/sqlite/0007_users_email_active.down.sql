@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uni_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS uni_users_email ON users (email);
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLog struct {
+	ID          uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt   time.Time       `gorm:"index" json:"created_at"`
+	ActorUserID *uint           `gorm:"index" json:"actor_user_id,omitempty"`
+	ActorEmail  string          `gorm:"size:255" json:"actor_email,omitempty"`
+	Action      string          `gorm:"size:30;not null;index" json:"action"`
+	EntityType  string          `gorm:"size:30;not null;index:idx_audit_entity" json:"entity_type"`
+	EntityID    string          `gorm:"size:64;index:idx_audit_entity" json:"entity_id,omitempty"`
+	IP          string          `gorm:"size:64" json:"ip,omitempty"`
+	RequestID   string          `gorm:"size:64;index" json:"request_id,omitempty"`
+	Changes     json.RawMessage `gorm:"type:text" json:"changes,omitempty"`
+}
+
+func (AuditLog) TableName() string { return "audit_log" }
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Project struct {
 	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -10,11 +14,20 @@ type Project struct {
 	StartDate   *time.Time `json:"start_date,omitempty"`
 	EndDate     *time.Time `json:"end_date,omitempty"`
 	OwnerUserID *uint      `json:"owner_user_id,omitempty"`
+	Version     uint       `gorm:"not null;default:1" json:"version"`
 
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	Owner User `gorm:"foreignKey:OwnerUserID;references:ID" json:"-"`
 }
 
 func (Project) TableName() string { return "projects" }
+
+func (p *Project) BeforeCreate(*gorm.DB) error {
+	if p.Version == 0 {
+		p.Version = 1
+	}
+	return nil
+}
@@ -1,16 +1,38 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	UserStatusActive      = "active"
+	UserStatusSuspended   = "suspended"
+	UserStatusDeactivated = "deactivated"
+)
 
 type User struct {
-	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name         string     `gorm:"size:100;not null" json:"name"`
-	Email        string     `gorm:"size:255;unique;not null" json:"email"`
-	PasswordHash string     `gorm:"size:255;not null" json:"-"`
-	Role         string     `gorm:"size:20;not null;default:user" json:"role"`
-	TokenVersion int        `gorm:"not null;default:0" json:"-"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	ID           uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string         `gorm:"size:100;not null" json:"name"`
+	Email        string         `gorm:"size:255;not null" json:"email"`
+	PasswordHash string         `gorm:"size:255;not null" json:"-"`
+	Role         string         `gorm:"size:20;not null;default:user" json:"role"`
+	Status       string         `gorm:"size:20;not null;default:active" json:"status"`
+	TokenVersion int            `gorm:"not null;default:0" json:"-"`
+	Version      uint           `gorm:"not null;default:1" json:"version"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    *time.Time     `json:"updated_at,omitempty"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (User) TableName() string { return "users" }
+
+func (u *User) BeforeCreate(*gorm.DB) error {
+	if u.Version == 0 {
+		u.Version = 1
+	}
+	return nil
+}
+
+func (u User) IsActive() bool { return u.Status == UserStatusActive }
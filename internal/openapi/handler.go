@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONHandler menyajikan dokumen apa adanya (tanpa envelope APIResponse). Di-encode sekali saat setup.
+func JSONHandler(doc map[string]interface{}) gin.HandlerFunc {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		panic("openapi: encode document: " + err.Error())
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", body)
+	}
+}
+
+// Versi swagger-ui-dist yang dimuat dari CDN.
+const swaggerUIVersion = "5.17.14"
+
+var uiPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+  <script nonce="{{.Nonce}}">
+    window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`))
+
+// uiCSP Content-Security-Policy halaman docs: asset hanya dari CDN swagger-ui, script inline hanya yang
+// membawa nonce, fetch spec dan "Try it out" ke origin sendiri. %s diisi nonce per request.
+const uiCSP = "default-src 'none'; script-src 'nonce-%s' https://unpkg.com; " +
+	"style-src 'unsafe-inline' https://unpkg.com; img-src 'self' data: https://unpkg.com; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// UIHandler halaman Swagger UI yang membaca spec dari specURL.
+func UIHandler(title, specURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nonce := newNonce()
+		c.Header("Content-Security-Policy", fmt.Sprintf(uiCSP, nonce))
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		_ = uiPage.Execute(c.Writer, struct{ Title, Version, SpecURL, Nonce string }{
+			title, swaggerUIVersion, specURL, nonce})
+	}
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
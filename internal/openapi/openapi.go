@@ -0,0 +1,297 @@
+// Package openapi membangun dokumen OpenAPI 3.1 dari daftar Operation; schema body dan response
+// diturunkan dari tipe Go lewat reflection (tag json + binding), jadi ikut berubah bersama kodenya.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// Access siapa yang boleh memanggil operation.
+type Access int
+
+const (
+	Public Access = iota
+	Bearer        // butuh token
+	Admin         // butuh token dengan role admin
+)
+
+// Param query parameter. Type default "string".
+type Param struct {
+	Name        string
+	Type        string
+	Format      string
+	Description string
+}
+
+// Operation satu route (method + path format gin, mis. /leads/:id).
+type Operation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Access      Access
+	Query       []Param
+
+	Request      interface{} // nilai contoh tipe body request, nil = tanpa body
+	RequestType  string      // default application/json
+	OptionalBody bool        // body boleh kosong
+	Response     interface{} // tipe field data pada envelope, nil = tanpa data
+	List         bool        // data berupa ListResult berisi Response
+	Status       int         // status sukses, default 200
+	IfMatch      bool        // wajib header If-Match (optimistic locking)
+	ETag         bool        // response membawa ETag; otomatis untuk IfMatch dan GET /:id
+	Errors       []int       // status error tambahan selain yang diturunkan otomatis
+}
+
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// OpenAPIPath mengubah path gin (/leads/:id) ke format OpenAPI (/leads/{id}).
+func OpenAPIPath(p string) string { return pathParam.ReplaceAllString(p, "{$1}") }
+
+// Build menyusun dokumen OpenAPI lengkap.
+func Build(info Info, ops []Operation) map[string]interface{} {
+	s := schemas{}
+	s.of(response.APIResponse{})
+	s.of(response.ListResult{})
+	s.of(response.Problem{})
+
+	paths := map[string]Schema{}
+	tags := map[string]bool{}
+	for _, op := range ops {
+		p := OpenAPIPath(op.Path)
+		if paths[p] == nil {
+			paths[p] = Schema{}
+		}
+		paths[p][strings.ToLower(op.Method)] = s.operation(op)
+		if op.Tag != "" {
+			tags[op.Tag] = true
+		}
+	}
+	var tagList []Schema
+	for _, t := range sortedKeys(tags) {
+		tagList = append(tagList, Schema{"name": t})
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": Schema{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"tags":  tagList,
+		"paths": paths,
+		"components": Schema{
+			"schemas":   s,
+			"responses": errorResponses(),
+			"securitySchemes": Schema{
+				"bearerAuth": Schema{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+func (s schemas) operation(op Operation) Schema {
+	out := Schema{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+	}
+	if op.Description != "" {
+		out["description"] = op.Description
+	}
+	if op.Tag != "" {
+		out["tags"] = []string{op.Tag}
+	}
+	if op.Access != Public {
+		out["security"] = []Schema{{"bearerAuth": []string{}}}
+	}
+
+	var params []Schema
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, Schema{"name": m[1], "in": "path", "required": true,
+			"schema": Schema{"type": "integer", "minimum": 1}})
+	}
+	if op.IfMatch {
+		params = append(params, Schema{"name": "If-Match", "in": "header", "required": true,
+			"description": `ETag dari GET sebelumnya, mis. "3"`, "schema": Schema{"type": "string"}})
+	}
+	for _, q := range op.Query {
+		typ := q.Type
+		if typ == "" {
+			typ = "string"
+		}
+		schema := Schema{"type": typ}
+		if q.Format != "" {
+			schema["format"] = q.Format
+		}
+		param := Schema{"name": q.Name, "in": "query", "schema": schema}
+		if q.Description != "" {
+			param["description"] = q.Description
+		}
+		params = append(params, param)
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	if op.Request != nil {
+		ct := op.RequestType
+		if ct == "" {
+			ct = "application/json"
+		}
+		out["requestBody"] = Schema{
+			"required": !op.OptionalBody,
+			"content":  Schema{ct: Schema{"schema": s.of(op.Request)}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses := Schema{strconv.Itoa(status): s.success(op, status)}
+	for _, code := range errorCodes(op) {
+		responses[strconv.Itoa(code)] = Schema{"$ref": "#/components/responses/" + errorResponseName[code]}
+	}
+	out["responses"] = responses
+	return out
+}
+
+// success response: envelope APIResponse dengan data sesuai op.Response.
+func (s schemas) success(op Operation, status int) Schema {
+	desc := http.StatusText(status)
+	if status == http.StatusNoContent {
+		return Schema{"description": desc}
+	}
+	body := ref("APIResponse")
+	if op.Response != nil {
+		data := s.of(op.Response)
+		if op.List {
+			data = Schema{"allOf": []Schema{ref("ListResult"), {
+				"properties": Schema{"items": Schema{"type": "array", "items": data}},
+			}}}
+		}
+		body = Schema{"allOf": []Schema{ref("APIResponse"), {"properties": Schema{"data": data}}}}
+	}
+	out := Schema{
+		"description": desc,
+		"content":     Schema{"application/json": Schema{"schema": body}},
+	}
+	if op.ETag || op.IfMatch || strings.HasSuffix(op.Path, "/:id") && op.Method == http.MethodGet {
+		out["headers"] = Schema{"ETag": Schema{"description": "Versi resource", "schema": Schema{"type": "string"}}}
+	}
+	return out
+}
+
+// errorCodes status error yang mungkin dikembalikan operation.
+func errorCodes(op Operation) []int {
+	set := map[int]bool{http.StatusInternalServerError: true}
+	if op.Access != Public {
+		// 403 juga untuk akun suspended/deactivated, bukan hanya route admin
+		set[http.StatusUnauthorized] = true
+		set[http.StatusForbidden] = true
+		set[http.StatusTooManyRequests] = true // rate limit read/write per user
+	}
+	if op.Request != nil {
+		set[http.StatusUnprocessableEntity] = true
+		set[http.StatusRequestEntityTooLarge] = true
+	}
+	if strings.Contains(op.Path, ":") {
+		set[http.StatusNotFound] = true
+	}
+	if op.IfMatch {
+		set[http.StatusPreconditionRequired] = true
+		set[http.StatusPreconditionFailed] = true
+	}
+	if op.RequestType == "application/merge-patch+json" {
+		set[http.StatusBadRequest] = true
+		set[http.StatusUnsupportedMediaType] = true
+	}
+	for _, c := range op.Errors {
+		set[c] = true
+	}
+	codes := make([]int, 0, len(set))
+	for c := range set {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+var errorResponseName = map[int]string{
+	http.StatusBadRequest:            "BadRequest",
+	http.StatusUnauthorized:          "Unauthorized",
+	http.StatusForbidden:             "Forbidden",
+	http.StatusNotFound:              "NotFound",
+	http.StatusConflict:              "Conflict",
+	http.StatusPreconditionFailed:    "PreconditionFailed",
+	http.StatusRequestEntityTooLarge: "PayloadTooLarge",
+	http.StatusUnsupportedMediaType:  "UnsupportedMediaType",
+	http.StatusUnprocessableEntity:   "ValidationError",
+	http.StatusPreconditionRequired:  "PreconditionRequired",
+	http.StatusTooManyRequests:       "TooManyRequests",
+	http.StatusInternalServerError:   "InternalError",
+	http.StatusServiceUnavailable:    "ServiceUnavailable",
+}
+
+// errorResponses components/responses untuk semua status error: envelope dengan success false dan
+// error.code, atau RFC 7807 kalau client mengirim Accept: application/problem+json.
+func errorResponses() Schema {
+	out := Schema{}
+	for code, name := range errorResponseName {
+		desc := http.StatusText(code)
+		switch code {
+		case http.StatusUnprocessableEntity:
+			desc = "Validasi gagal (VALIDATION_FAILED, error.fields per field) atau body bukan JSON (INVALID_JSON)"
+		case http.StatusPreconditionFailed:
+			desc = "Versi di If-Match sudah usang (VERSION_MISMATCH); data berisi representasi terbaru dan header ETag versinya"
+		case http.StatusServiceUnavailable:
+			desc = "Belum siap menerima traffic (NOT_READY); data berisi hasil tiap check"
+		case http.StatusRequestEntityTooLarge:
+			desc = "Body request melewati MAX_BODY_BYTES (PAYLOAD_TOO_LARGE)"
+		case http.StatusTooManyRequests:
+			desc = "Rate limit terlampaui (RATE_LIMITED); coba lagi setelah header Retry-After (detik)"
+		}
+		body := Schema{"allOf": []Schema{ref("APIResponse"), {
+			"properties": Schema{"success": Schema{"const": false}},
+			"required":   []string{"error"},
+		}}}
+		out[name] = Schema{"description": desc, "content": Schema{
+			"application/json":   Schema{"schema": body},
+			response.MIMEProblem: Schema{"schema": ref("Problem")},
+		}}
+	}
+	return out
+}
+
+// operationID mis. GET /leads/:id/restore -> getLeadsIdRestore.
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(op.Path, func(r rune) bool { return r == '/' || r == '-' || r == ':' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Schema JSON Schema (dialek OpenAPI 3.1).
+type Schema map[string]interface{}
+
+// Tipe yang punya representasi JSON sendiri, tidak mengikuti field struct-nya.
+var knownTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):       {"type": "string", "format": "date-time"},
+	reflect.TypeOf(gorm.DeletedAt{}):  {"type": []string{"string", "null"}, "format": "date-time"},
+	reflect.TypeOf(json.RawMessage{}): {},
+}
+
+// schemas mengumpulkan schema struct bernama ke components/schemas.
+type schemas map[string]Schema
+
+func ref(name string) Schema { return Schema{"$ref": "#/components/schemas/" + name} }
+
+// of schema untuk tipe Go v. Struct bernama didaftarkan ke components lalu di-reference.
+func (s schemas) of(v interface{}) Schema {
+	if v == nil {
+		return Schema{}
+	}
+	return s.typ(reflect.TypeOf(v))
+}
+
+func (s schemas) typ(t reflect.Type) Schema {
+	if known, ok := knownTypes[t]; ok {
+		return known
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(s.typ(t.Elem()))
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": s.typ(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": s.typ(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := componentName(t)
+		if _, ok := s[name]; !ok {
+			s[name] = Schema{} // tandai dulu supaya tipe rekursif tidak loop
+			s[name] = s.object(t)
+		}
+		return ref(name)
+	default:
+		return Schema{}
+	}
+}
+
+func componentName(t reflect.Type) string {
+	r := []rune(t.Name())
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// object schema struct dari tag json (nama field) dan binding (required, email, min, oneof).
+func (s schemas) object(t reflect.Type) Schema {
+	props := Schema{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := s.object(f.Type)
+			for k, v := range embedded["properties"].(Schema) {
+				props[k] = v
+			}
+			if req, ok := embedded["required"].([]string); ok {
+				required = append(required, req...)
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := s.typ(f.Type)
+		if applyBinding(&prop, f.Tag.Get("binding")) {
+			required = append(required, name)
+		} else if f.Type.Kind() != reflect.Ptr && !strings.Contains(opts, "omitempty") && f.Tag.Get("binding") == "" {
+			// field response tanpa omitempty selalu ada di output
+			required = append(required, name)
+		}
+		props[name] = prop
+	}
+	out := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+// applyBinding menerjemahkan tag validator ke keyword JSON Schema. Return true kalau field required.
+func applyBinding(prop *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	// schema $ref / knownTypes tidak boleh diubah, salin dulu
+	p := Schema{}
+	for k, v := range *prop {
+		p[k] = v
+	}
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			p["format"] = "email"
+		case "min":
+			if n, err := strconv.Atoi(arg); err == nil {
+				if isType(p, "string") {
+					p["minLength"] = n
+				} else {
+					p["minimum"] = n
+				}
+			}
+		case "oneof":
+			p["enum"] = strings.Fields(arg)
+		}
+	}
+	*prop = p
+	return required
+}
+
+func isType(s Schema, typ string) bool {
+	switch t := s["type"].(type) {
+	case string:
+		return t == typ
+	case []string:
+		return len(t) > 0 && t[0] == typ
+	}
+	return false
+}
+
+// nullable menambahkan null ke tipe yang diizinkan.
+func nullable(s Schema) Schema {
+	if t, ok := s["type"].(string); ok {
+		out := Schema{}
+		for k, v := range s {
+			out[k] = v
+		}
+		out["type"] = []string{t, "null"}
+		return out
+	}
+	if _, ok := s["type"]; ok || len(s) == 0 {
+		return s
+	}
+	return Schema{"oneOf": []Schema{s, {"type": "null"}}}
+}
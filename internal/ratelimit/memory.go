@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+// sweepInterval jarak minimal antar pembersihan bucket yang sudah penuh lagi.
+const sweepInterval = time.Minute
+
+// MemoryStore Store di memori proses. Bucket yang sudah terisi penuh dibuang berkala karena sama saja
+// dengan bucket baru, jadi memori hanya dipakai oleh client yang aktif.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time // waktu tokens dihitung
+	full   time.Time // waktu bucket penuh lagi kalau tidak ada request
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rate config.Rate) (Result, error) {
+	now := s.now()
+	capacity := float64(rate.Requests)
+	perToken := rate.Per / time.Duration(rate.Requests)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity}
+		s.buckets[key] = b
+	} else {
+		b.tokens = min(capacity, b.tokens+float64(now.Sub(b.last))/float64(perToken))
+	}
+	b.last = now
+
+	res := Result{Limit: rate.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
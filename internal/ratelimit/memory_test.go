@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	rate := config.Rate{Requests: 3, Per: time.Minute} // satu token tiap 20 detik
+	ctx := context.Background()
+
+	take := func(key string) Result {
+		t.Helper()
+		res, err := s.Take(ctx, key, rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	for i := 2; i >= 0; i-- {
+		if res := take("a"); !res.Allowed || res.Remaining != i || res.Limit != 3 {
+			t.Fatalf("take: %+v, want allowed with %d remaining", res, i)
+		}
+	}
+	res := take("a")
+	if res.Allowed || res.RetryAfter != 20*time.Second || res.Reset != time.Minute {
+		t.Fatalf("empty bucket: %+v, want denied, retry after 20s, reset 1m", res)
+	}
+	if res := take("b"); !res.Allowed {
+		t.Fatal("other key should have its own bucket")
+	}
+
+	now = now.Add(20 * time.Second)
+	if res := take("a"); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("after one refill period: %+v, want one token", res)
+	}
+
+	// Bucket yang sudah penuh lagi dibuang saat sweep.
+	now = now.Add(2 * time.Minute)
+	take("c")
+	if _, ok := s.buckets["a"]; ok || len(s.buckets) != 1 {
+		t.Fatalf("buckets after sweep = %v, want only c", s.buckets)
+	}
+}
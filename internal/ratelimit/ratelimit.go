@@ -0,0 +1,123 @@
+// Package ratelimit rate limit token bucket per kelompok route (auth, read, write, export). Kunci bucket
+// adalah ID user kalau request sudah login, selain itu IP client. State bucket disimpan lewat Store:
+// MemoryStore untuk satu instance; beberapa instance di belakang load balancer butuh Store bersama
+// (mis. Redis) supaya batasnya tidak berlipat sebanyak jumlah instance.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// Kelompok route; masing-masing punya bucket sendiri per user/IP.
+const (
+	GroupAuth   = "auth"
+	GroupRead   = "read"
+	GroupWrite  = "write"
+	GroupExport = "export"
+)
+
+// Result hasil satu Take.
+type Result struct {
+	Allowed    bool
+	Limit      int           // kapasitas bucket
+	Remaining  int           // token tersisa setelah request ini
+	Reset      time.Duration // sampai bucket penuh lagi
+	RetryAfter time.Duration // sampai ada token lagi, hanya kalau !Allowed
+}
+
+// Store mengambil satu token dari bucket key dengan batas rate. Implementasi untuk Redis cukup
+// menyimpan (token, waktu update terakhir) per key dan menjalankan perhitungan yang sama dengan
+// MemoryStore secara atomik (mis. script Lua), dengan TTL sebesar rate.Per.
+type Store interface {
+	Take(ctx context.Context, key string, rate config.Rate) (Result, error)
+}
+
+// Limiter membuat middleware rate limit dari konfigurasi RATE_LIMIT_*.
+type Limiter struct {
+	store Store
+	cfg   config.RateLimit
+}
+
+func New(store Store, cfg config.RateLimit) *Limiter { return &Limiter{store: store, cfg: cfg} }
+
+// Group middleware dengan batas kelompok group untuk semua request.
+func (l *Limiter) Group(group string) gin.HandlerFunc {
+	rate := l.rate(group)
+	if !l.cfg.Enabled || rate.IsZero() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) { l.take(c, group, rate) }
+}
+
+// Methods middleware yang memakai batas read untuk GET/HEAD/OPTIONS dan write untuk method lain.
+func (l *Limiter) Methods() gin.HandlerFunc {
+	read, write := l.Group(GroupRead), l.Group(GroupWrite)
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			read(c)
+		default:
+			write(c)
+		}
+	}
+}
+
+func (l *Limiter) rate(group string) config.Rate {
+	switch group {
+	case GroupAuth:
+		return l.cfg.Auth
+	case GroupRead:
+		return l.cfg.Read
+	case GroupWrite:
+		return l.cfg.Write
+	case GroupExport:
+		return l.cfg.Export
+	}
+	return config.Rate{}
+}
+
+func (l *Limiter) take(c *gin.Context, group string, rate config.Rate) {
+	res, err := l.store.Take(c.Request.Context(), group+":"+key(c), rate)
+	if err != nil {
+		// Store tidak bisa dihubungi: lebih baik request lolos daripada API mati total
+		slog.WarnContext(c.Request.Context(), "rate limit store failed", "group", group, "error", err)
+		c.Next()
+		return
+	}
+	h := c.Writer.Header()
+	h.Set("RateLimit-Policy", strconv.Itoa(rate.Requests)+";w="+strconv.Itoa(seconds(rate.Per)))
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(max(1, seconds(res.RetryAfter))))
+		response.TooManyRequests(c, "Rate limit exceeded, try again later")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// key ID user dari AuthRequired kalau ada, selain itu IP client.
+func key(c *gin.Context) string {
+	if v, ok := c.Get("user"); ok {
+		if u, ok := v.(models.User); ok {
+			return "user:" + strconv.FormatUint(uint64(u.ID), 10)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// seconds dibulatkan ke atas supaya client tidak mencoba sebelum waktunya.
+func seconds(d time.Duration) int { return int(math.Ceil(d.Seconds())) }
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+)
+
+// GormStore implementasi Store di atas *gorm.DB.
+type GormStore struct {
+	DB *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore { return &GormStore{DB: db} }
+
+func (s *GormStore) Leads() LeadRepository       { return &GormLeadRepository{DB: s.DB} }
+func (s *GormStore) Deals() DealRepository       { return &GormDealRepository{DB: s.DB} }
+func (s *GormStore) Projects() ProjectRepository { return &GormProjectRepository{DB: s.DB} }
+func (s *GormStore) Users() UserRepository       { return &GormUserRepository{DB: s.DB} }
+func (s *GormStore) PasswordResets() PasswordResetRepository {
+	return &GormPasswordResetRepository{DB: s.DB}
+}
+
+func (s *GormStore) Audit() AuditRepository { return &GormAuditRepository{DB: s.DB} }
+
+func (s *GormStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return database.Transaction(ctx, s.DB, func(tx *gorm.DB) error {
+		return fn(&GormStore{DB: tx})
+	})
+}
+
+func (s *GormStore) PurgeTrash(ctx context.Context, before time.Time) (map[string]int64, error) {
+	return database.PurgeTrash(s.DB.WithContext(ctx), before)
+}
+
+// translate menyeragamkan error GORM/driver menjadi error package ini.
+func translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case database.IsUniqueViolation(err):
+		return ErrDuplicate
+	default:
+		return err
+	}
+}
+
+// paginate menghitung total lalu mengambil satu halaman q ke dest.
+func paginate(q *gorm.DB, order string, p Page, dest interface{}) (int64, error) {
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, q.Order(order).Limit(p.PerPage).Offset(p.Offset()).Find(dest).Error
+}
+
+// keysetPage mengambil satu halaman q ke dest. Dengan urutan default (created_at DESC) atau hanya created_at,
+// p.After/p.Before memulai halaman dari cursor tanpa OFFSET; tanpa cursor dipakai offset p.Page. Satu baris
+// ekstra diambil untuk tahu apakah masih ada halaman berikutnya. Urutan lain memakai offset saja, tanpa
+// cursor. COUNT(*) hanya kalau p.WithTotal.
+func keysetPage[T any](q *gorm.DB, pk string, p Page, query Query, key func(T) Cursor) ([]T, PageInfo, error) {
+	var info PageInfo
+	if p.WithTotal {
+		var total int64
+		if err := q.Count(&total).Error; err != nil {
+			return nil, info, err
+		}
+		info.Total = &total
+	}
+	desc, keyset := query.Keyset()
+	if !keyset {
+		var rows []T
+		for _, s := range query.Sort {
+			q = q.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+		}
+		last := query.Sort[len(query.Sort)-1]
+		q = q.Order(clause.OrderByColumn{Column: clause.Column{Name: pk}, Desc: last.Desc})
+		return rows, info, q.Limit(p.PerPage).Offset(p.Offset()).Find(&rows).Error
+	}
+
+	// after/before: operator untuk baris sesudah/sebelum cursor di urutan list
+	after, before, dir, back := "<", ">", "DESC", "ASC"
+	if !desc {
+		after, before, dir, back = ">", "<", "ASC", "DESC"
+	}
+	order := "created_at " + dir + ", " + pk + " " + dir
+	switch {
+	case p.After != nil:
+		q = q.Where("created_at "+after+" ? OR (created_at = ? AND "+pk+" "+after+" ?)", p.After.CreatedAt, p.After.CreatedAt, p.After.ID)
+	case p.Before != nil:
+		q = q.Where("created_at "+before+" ? OR (created_at = ? AND "+pk+" "+before+" ?)", p.Before.CreatedAt, p.Before.CreatedAt, p.Before.ID)
+		order = "created_at " + back + ", " + pk + " " + back
+	case p.Page > 1:
+		q = q.Offset(p.Offset())
+	}
+	var rows []T
+	if err := q.Order(order).Limit(p.PerPage + 1).Find(&rows).Error; err != nil {
+		return nil, info, err
+	}
+	more := len(rows) > p.PerPage
+	if more {
+		rows = rows[:p.PerPage]
+	}
+	if p.Before != nil {
+		slices.Reverse(rows)
+	}
+	return rows, pageInfo(info, p, rows, more, key), nil
+}
+
+// filter menerapkan query.Where dan mengecek semua kolom query ada di fields. Nama kolom ditulis lewat
+// clause.Column (di-quote dialect) dan nilai selalu lewat placeholder.
+func filter(q *gorm.DB, fields []Field, query Query) (*gorm.DB, error) {
+	known := func(column string) bool {
+		return slices.ContainsFunc(fields, func(f Field) bool { return f.Column == column })
+	}
+	for _, s := range query.Sort {
+		if !known(s.Column) {
+			return nil, fmt.Errorf("repository: unknown sort column %q", s.Column)
+		}
+	}
+	ops := map[Op]string{OpEq: "=", OpNe: "<>", OpGt: ">", OpGte: ">=", OpLt: "<", OpLte: "<="}
+	for _, c := range query.Where {
+		if !known(c.Column) || len(c.Values) == 0 {
+			return nil, fmt.Errorf("repository: invalid condition on %q", c.Column)
+		}
+		col := clause.Column{Name: c.Column}
+		switch c.Op {
+		case OpIn:
+			q = q.Where("? IN ?", col, c.Values)
+		case OpLike:
+			q = q.Where("? LIKE ?"+likeEscape(q), col, like(fmt.Sprint(c.Values[0])))
+		case OpNull:
+			if isNull, _ := c.Values[0].(bool); isNull {
+				q = q.Where("? IS NULL", col)
+			} else {
+				q = q.Where("? IS NOT NULL", col)
+			}
+		default:
+			op, ok := ops[c.Op]
+			if !ok {
+				return nil, fmt.Errorf("repository: unknown operator %q", c.Op)
+			}
+			q = q.Where("? "+op+" ?", col, c.Values[0])
+		}
+	}
+	return q, nil
+}
+
+// pageInfo mengisi cursor Next/Prev dari baris pertama dan terakhir halaman. more berarti masih ada baris
+// ke arah pembacaan (sesudah halaman untuk After/offset, sebelum halaman untuk Before).
+func pageInfo[T any](info PageInfo, p Page, rows []T, more bool, key func(T) Cursor) PageInfo {
+	if len(rows) == 0 {
+		return info
+	}
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	if p.Before != nil {
+		info.Next = &last
+		if more {
+			info.Prev = &first
+		}
+		return info
+	}
+	if more {
+		info.Next = &last
+	}
+	if p.After != nil || p.Page > 1 {
+		info.Prev = &first
+	}
+	return info
+}
+
+// updateIfVersion menulis semua kolom dest (termasuk nil) hanya kalau version di DB masih `version`.
+// Kolom yang tidak boleh ditimpa dari struct (mis. token_version) bisa ditambahkan lewat omit.
+func updateIfVersion(db *gorm.DB, dest interface{}, pkColumn string, version uint, omit ...string) (bool, error) {
+	omit = append(omit, pkColumn, "created_at", "deleted_at", clause.Associations)
+	res := db.Model(dest).Where("version = ?", version).
+		Select("*").Omit(omit...).
+		Updates(dest)
+	return res.RowsAffected > 0, translate(res.Error)
+}
+
+// softDelete mengisi deleted_at baris aktif dengan pk = id. false kalau baris tidak ada.
+func softDelete(db *gorm.DB, model interface{}, pkColumn string, id uint, at time.Time) (bool, error) {
+	res := db.Model(model).Where(pkColumn+" = ?", id).Update("deleted_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+func getTrashed(db *gorm.DB, dest interface{}, pkColumn string, id uint) error {
+	return translate(db.Unscoped().Where(pkColumn+" = ? AND deleted_at IS NOT NULL", id).First(dest).Error)
+}
+
+// listTrashed baris soft-deleted, terbaru dihapus duluan.
+func listTrashed(db *gorm.DB, model, dest interface{}, p Page) (int64, error) {
+	q := db.Unscoped().Model(model).Where("deleted_at IS NOT NULL")
+	return paginate(q, "deleted_at DESC", p, dest)
+}
+
+func restore(db *gorm.DB, model interface{}, pkColumn string, id uint) error {
+	return db.Unscoped().Model(model).Where(pkColumn+" = ?", id).Update("deleted_at", nil).Error
+}
+
+func inRange(q *gorm.DB, column string, r DateRange) *gorm.DB {
+	if r.From != nil {
+		q = q.Where(column+" >= ?", *r.From)
+	}
+	if r.To != nil {
+		q = q.Where(column+" < ?", *r.To)
+	}
+	return q
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// like pola '%v%' yang mencari v apa adanya: %, _ dan \ di v di-escape dengan \, jadi pola ini selalu
+// dipakai bersama likeEscape.
+func like(v string) string { return "%" + likeEscaper.Replace(v) + "%" }
+
+// likeEscape klausa ESCAPE untuk pola dari like. Di MySQL backslash juga escape di string literal, jadi
+// harus ditulis dua kali; PostgreSQL (standard_conforming_strings) dan SQLite memakainya apa adanya.
+func likeEscape(q *gorm.DB) string {
+	if q.Dialector.Name() == database.MySQL {
+		return ` ESCAPE '\\'`
+	}
+	return ` ESCAPE '\'`
+}
+
+// search WHERE kolom LIKE '%v%' di salah satu columns, untuk parameter pencarian q.
+func search(q *gorm.DB, v string, columns ...string) *gorm.DB {
+	conds := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, col := range columns {
+		conds[i], args[i] = col+" LIKE ?"+likeEscape(q), like(v)
+	}
+	return q.Where(strings.Join(conds, " OR "), args...)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormAuditRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormAuditRepository(db *gorm.DB) *GormAuditRepository { return &GormAuditRepository{DB: db} }
+
+func (r *GormAuditRepository) Create(ctx context.Context, a *models.AuditLog) error {
+	return translate(r.DB.WithContext(ctx).Create(a).Error)
+}
+
+func (r *GormAuditRepository) List(ctx context.Context, f AuditFilter, p Page) ([]models.AuditLog, PageInfo, error) {
+	q := database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.AuditLog{})
+	q, err := filter(q, AuditFields, f.Query)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	q = inRange(q, "created_at", f.Created)
+	return keysetPage(q, "id", p, f.Query, func(v models.AuditLog) Cursor { return Cursor{CreatedAt: v.CreatedAt, ID: v.ID} })
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormDealRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormDealRepository(db *gorm.DB) *GormDealRepository { return &GormDealRepository{DB: db} }
+
+func (r *GormDealRepository) Create(ctx context.Context, d *models.Deal) error {
+	return translate(r.DB.WithContext(ctx).Create(d).Error)
+}
+
+func (r *GormDealRepository) SoftDeleteByLead(ctx context.Context, leadID uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Deal{}).Where("lead_id = ?", leadID).Update("deleted_at", at).Error
+}
+
+func (r *GormDealRepository) RestoreByLead(ctx context.Context, leadID uint, deletedAt time.Time) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&models.Deal{}).
+		Where("lead_id = ? AND deleted_at = ?", leadID, deletedAt).
+		Update("deleted_at", nil).Error
+}
+
+func (r *GormDealRepository) Stats(ctx context.Context, dr DateRange) (DealStats, error) {
+	db := database.ReadReplica(r.DB.WithContext(ctx))
+	var agg struct {
+		Count int64   `gorm:"column:count"`
+		Total int64   `gorm:"column:total"`
+		Avg   float64 `gorm:"column:avg"`
+	}
+	if err := inRange(db.Model(&models.Deal{}), "closed_at", dr).
+		Select("COUNT(*) as count, COALESCE(SUM(amount_idr),0) as total, COALESCE(AVG(term_months),0) as avg").
+		Scan(&agg).Error; err != nil {
+		return DealStats{}, err
+	}
+	var rows []struct {
+		Name  *string `gorm:"column:name"`
+		Count int64   `gorm:"column:count"`
+		Total int64   `gorm:"column:total"`
+	}
+	if err := inRange(db.Model(&models.Deal{}), "closed_at", dr).
+		Select("stage AS name, COUNT(*) AS count, COALESCE(SUM(amount_idr),0) AS total").Group("stage").
+		Scan(&rows).Error; err != nil {
+		return DealStats{}, err
+	}
+	st := DealStats{Count: agg.Count, TotalAmountIDR: agg.Total, AvgTermMonths: agg.Avg,
+		ByStage: map[string]int64{}, AmountByStage: map[string]int64{}}
+	for _, r := range rows {
+		stage := ""
+		if r.Name != nil {
+			stage = *r.Name
+		}
+		st.ByStage[stage], st.AmountByStage[stage] = r.Count, r.Total
+	}
+	return st, nil
+}
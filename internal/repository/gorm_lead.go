@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormLeadRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormLeadRepository(db *gorm.DB) *GormLeadRepository { return &GormLeadRepository{DB: db} }
+
+func (r *GormLeadRepository) Create(ctx context.Context, l *models.Lead) error {
+	return translate(r.DB.WithContext(ctx).Create(l).Error)
+}
+
+func (r *GormLeadRepository) Get(ctx context.Context, id uint) (models.Lead, error) {
+	var l models.Lead
+	err := r.DB.WithContext(ctx).First(&l, "lead_id = ?", id).Error
+	return l, translate(err)
+}
+
+func (r *GormLeadRepository) List(ctx context.Context, f LeadFilter, p Page) ([]models.Lead, PageInfo, error) {
+	q := database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.Lead{})
+	q, err := filter(q, LeadFields, f.Query)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	if f.Q != "" {
+		q = search(q, f.Q, "company_name", "contact_name", "email")
+	}
+	return keysetPage(q, "lead_id", p, f.Query, func(v models.Lead) Cursor { return Cursor{CreatedAt: v.CreatedAt, ID: v.LeadID} })
+}
+
+func (r *GormLeadRepository) UpdateIfVersion(ctx context.Context, l *models.Lead, version uint) (bool, error) {
+	return updateIfVersion(r.DB.WithContext(ctx), l, "lead_id", version)
+}
+
+func (r *GormLeadRepository) SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error) {
+	return softDelete(r.DB.WithContext(ctx), &models.Lead{}, "lead_id", id, at)
+}
+
+func (r *GormLeadRepository) GetTrashed(ctx context.Context, id uint) (models.Lead, error) {
+	var l models.Lead
+	return l, getTrashed(r.DB.WithContext(ctx), &l, "lead_id", id)
+}
+
+func (r *GormLeadRepository) ListTrashed(ctx context.Context, p Page) ([]models.Lead, int64, error) {
+	var leads []models.Lead
+	total, err := listTrashed(r.DB.WithContext(ctx), &models.Lead{}, &leads, p)
+	return leads, total, err
+}
+
+func (r *GormLeadRepository) Restore(ctx context.Context, id uint) error {
+	return restore(r.DB.WithContext(ctx), &models.Lead{}, "lead_id", id)
+}
+
+func (r *GormLeadRepository) Count(ctx context.Context, dr DateRange) (int64, error) {
+	var n int64
+	err := inRange(database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.Lead{}), "created_at", dr).Count(&n).Error
+	return n, err
+}
+
+// kolom yang boleh dipakai CountBy, nama kolom masuk ke SQL apa adanya
+var leadGroupColumns = map[string]bool{"status": true, "source": true, "region": true}
+
+func (r *GormLeadRepository) CountBy(ctx context.Context, column string, dr DateRange) (map[string]int64, error) {
+	if !leadGroupColumns[column] {
+		return nil, fmt.Errorf("cannot group leads by %q", column)
+	}
+	var rows []groupCount
+	err := inRange(database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.Lead{}), "created_at", dr).
+		Select(column + " AS name, COUNT(*) AS count").Group(column).Scan(&rows).Error
+	return groupMap(rows), err
+}
+
+func (r *GormLeadRepository) ReassignSalesRep(ctx context.Context, from, to string) error {
+	return r.DB.WithContext(ctx).Model(&models.Lead{}).Where("sales_rep = ?", from).Updates(map[string]any{
+		"sales_rep": to,
+		"version":   gorm.Expr("version + 1"),
+	}).Error
+}
+
+type groupCount struct {
+	Name  *string `gorm:"column:name"`
+	Count int64   `gorm:"column:count"`
+}
+
+func groupMap(rows []groupCount) map[string]int64 {
+	out := map[string]int64{}
+	for _, r := range rows {
+		k := ""
+		if r.Name != nil {
+			k = *r.Name
+		}
+		out[k] = r.Count
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormPasswordResetRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormPasswordResetRepository(db *gorm.DB) *GormPasswordResetRepository {
+	return &GormPasswordResetRepository{DB: db}
+}
+
+func (r *GormPasswordResetRepository) Create(ctx context.Context, pr *models.PasswordReset) error {
+	return translate(r.DB.WithContext(ctx).Create(pr).Error)
+}
+
+func (r *GormPasswordResetRepository) GetByToken(ctx context.Context, token string) (models.PasswordReset, error) {
+	var pr models.PasswordReset
+	err := r.DB.WithContext(ctx).Where("token = ?", token).First(&pr).Error
+	return pr, translate(err)
+}
+
+func (r *GormPasswordResetRepository) MarkUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.PasswordReset{}).Where("id = ?", id).Update("used_at", at).Error
+}
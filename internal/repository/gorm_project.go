@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormProjectRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormProjectRepository(db *gorm.DB) *GormProjectRepository {
+	return &GormProjectRepository{DB: db}
+}
+
+func (r *GormProjectRepository) Create(ctx context.Context, p *models.Project) error {
+	return translate(r.DB.WithContext(ctx).Create(p).Error)
+}
+
+func (r *GormProjectRepository) Get(ctx context.Context, id uint) (models.Project, error) {
+	var p models.Project
+	err := r.DB.WithContext(ctx).First(&p, "id = ?", id).Error
+	return p, translate(err)
+}
+
+func (r *GormProjectRepository) List(ctx context.Context, f ProjectFilter, p Page) ([]models.Project, PageInfo, error) {
+	q := database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.Project{})
+	q, err := filter(q, ProjectFields, f.Query)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	if f.Q != "" {
+		q = search(q, f.Q, "name", "description")
+	}
+	return keysetPage(q, "id", p, f.Query, func(v models.Project) Cursor { return Cursor{CreatedAt: v.CreatedAt, ID: v.ID} })
+}
+
+func (r *GormProjectRepository) UpdateIfVersion(ctx context.Context, p *models.Project, version uint) (bool, error) {
+	return updateIfVersion(r.DB.WithContext(ctx), p, "id", version)
+}
+
+func (r *GormProjectRepository) SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error) {
+	return softDelete(r.DB.WithContext(ctx), &models.Project{}, "id", id, at)
+}
+
+func (r *GormProjectRepository) GetTrashed(ctx context.Context, id uint) (models.Project, error) {
+	var p models.Project
+	return p, getTrashed(r.DB.WithContext(ctx), &p, "id", id)
+}
+
+func (r *GormProjectRepository) ListTrashed(ctx context.Context, p Page) ([]models.Project, int64, error) {
+	var items []models.Project
+	total, err := listTrashed(r.DB.WithContext(ctx), &models.Project{}, &items, p)
+	return items, total, err
+}
+
+func (r *GormProjectRepository) Restore(ctx context.Context, id uint) error {
+	return restore(r.DB.WithContext(ctx), &models.Project{}, "id", id)
+}
+
+func (r *GormProjectRepository) ReassignOwner(ctx context.Context, fromUserID, toUserID uint) error {
+	return r.DB.WithContext(ctx).Model(&models.Project{}).Where("owner_user_id = ?", fromUserID).Updates(map[string]any{
+		"owner_user_id": toUserID,
+		"version":       gorm.Expr("version + 1"),
+	}).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+type GormUserRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository { return &GormUserRepository{DB: db} }
+
+func (r *GormUserRepository) Create(ctx context.Context, u *models.User) error {
+	return translate(r.DB.WithContext(ctx).Create(u).Error)
+}
+
+func (r *GormUserRepository) Get(ctx context.Context, id uint) (models.User, error) {
+	var u models.User
+	err := r.DB.WithContext(ctx).First(&u, "id = ?", id).Error
+	return u, translate(err)
+}
+
+func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var u models.User
+	err := r.DB.WithContext(ctx).Where("email = ?", email).First(&u).Error
+	return u, translate(err)
+}
+
+func (r *GormUserRepository) List(ctx context.Context, f UserFilter, p Page) ([]models.User, PageInfo, error) {
+	q := database.ReadReplica(r.DB.WithContext(ctx)).Model(&models.User{})
+	q, err := filter(q, UserFields, f.Query)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	if f.Q != "" {
+		q = search(q, f.Q, "name", "email")
+	}
+	return keysetPage(q, "id", p, f.Query, func(v models.User) Cursor { return Cursor{CreatedAt: v.CreatedAt, ID: v.ID} })
+}
+
+func (r *GormUserRepository) UpdateIfVersion(ctx context.Context, u *models.User, version uint) (bool, error) {
+	return updateIfVersion(r.DB.WithContext(ctx), u, "id", version, "password_hash", "token_version", "status")
+}
+
+func (r *GormUserRepository) SetStatus(ctx context.Context, id uint, status string) error {
+	return r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+		"status":        status,
+		"token_version": gorm.Expr("token_version + 1"),
+		"version":       gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *GormUserRepository) UpdatePassword(ctx context.Context, id uint, hash string) error {
+	return r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+		"password_hash": hash,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error
+}
+
+func (r *GormUserRepository) RevokeTokens(ctx context.Context, id uint) error {
+	return r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// CountActiveAdmins memakai SELECT ... FOR UPDATE atas semua admin aktif (urut id, termasuk excludeID)
+// supaya dua transaksi selalu mengunci dengan urutan yang sama. SQLite tidak punya row lock; di sana
+// penulis sudah diserialkan per database.
+func (r *GormUserRepository) CountActiveAdmins(ctx context.Context, excludeID uint) (int64, error) {
+	var ids []uint
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND status = ?", "admin", models.UserStatusActive).
+		Order("id").Pluck("id", &ids).Error
+	var n int64
+	for _, id := range ids {
+		if id != excludeID {
+			n++
+		}
+	}
+	return n, err
+}
+
+func (r *GormUserRepository) CountByName(ctx context.Context, name string) (int64, error) {
+	var n int64
+	err := r.DB.WithContext(ctx).Unscoped().Model(&models.User{}).Where("name = ?", name).Count(&n).Error
+	return n, err
+}
+
+func (r *GormUserRepository) SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error) {
+	return softDelete(r.DB.WithContext(ctx), &models.User{}, "id", id, at)
+}
+
+func (r *GormUserRepository) GetTrashed(ctx context.Context, id uint) (models.User, error) {
+	var u models.User
+	return u, getTrashed(r.DB.WithContext(ctx), &u, "id", id)
+}
+
+func (r *GormUserRepository) ListTrashed(ctx context.Context, p Page) ([]models.User, int64, error) {
+	var users []models.User
+	total, err := listTrashed(r.DB.WithContext(ctx), &models.User{}, &users, p)
+	return users, total, err
+}
+
+func (r *GormUserRepository) Restore(ctx context.Context, id uint) error {
+	return translate(restore(r.DB.WithContext(ctx), &models.User{}, "id", id))
+}
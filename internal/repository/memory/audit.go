@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type auditRow models.AuditLog
+
+func (r auditRow) key() uint                    { return r.ID }
+func (r auditRow) created() time.Time           { return r.CreatedAt }
+func (r auditRow) deletedAt() (time.Time, bool) { return time.Time{}, false }
+
+func (r auditRow) column(name string) any {
+	switch name {
+	case "id":
+		return r.ID
+	case "created_at":
+		return r.CreatedAt
+	case "actor_user_id":
+		return r.ActorUserID
+	case "action":
+		return r.Action
+	case "entity_type":
+		return r.EntityType
+	case "entity_id":
+		return r.EntityID
+	case "ip":
+		return r.IP
+	case "request_id":
+		return r.RequestID
+	}
+	return nil
+}
+
+type auditRepo struct{ s *Store }
+
+func (r auditRepo) Create(_ context.Context, a *models.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a.ID = r.s.data.next("audit_log")
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = r.s.now()
+	}
+	r.s.data.audit[a.ID] = auditRow(*a)
+	return nil
+}
+
+func (r auditRepo) List(_ context.Context, f repository.AuditFilter, p repository.Page) ([]models.AuditLog, repository.PageInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := alive(r.s.data.audit, func(a auditRow) bool {
+		return inRange(a.CreatedAt, f.Created) && matches(a, f.Where)
+	})
+	items, info := keyset(rows, f.Query, p)
+	out := make([]models.AuditLog, len(items))
+	for i, a := range items {
+		out[i] = models.AuditLog(a)
+	}
+	return out, info, nil
+}
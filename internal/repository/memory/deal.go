@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type dealRow models.Deal
+
+func (r dealRow) key() uint          { return r.DealID }
+func (r dealRow) created() time.Time { return r.ClosedAt }
+func (r dealRow) deletedAt() (time.Time, bool) {
+	return r.DeletedAt.Time, r.DeletedAt.Valid
+}
+
+type dealRepo struct{ s *Store }
+
+func (r dealRepo) Create(_ context.Context, d *models.Deal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d.DealID = r.s.data.next("deals")
+	if d.Currency == "" {
+		d.Currency = "IDR"
+	}
+	row := *d
+	row.Lead = models.Lead{}
+	r.s.data.deals[d.DealID] = dealRow(row)
+	return nil
+}
+
+func (r dealRepo) SoftDeleteByLead(_ context.Context, leadID uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, d := range r.s.data.deals {
+		if d.LeadID == leadID && !d.DeletedAt.Valid {
+			d.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+			r.s.data.deals[id] = d
+		}
+	}
+	return nil
+}
+
+func (r dealRepo) RestoreByLead(_ context.Context, leadID uint, deletedAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, d := range r.s.data.deals {
+		if d.LeadID == leadID && d.DeletedAt.Valid && d.DeletedAt.Time.Equal(deletedAt) {
+			d.DeletedAt = gorm.DeletedAt{}
+			r.s.data.deals[id] = d
+		}
+	}
+	return nil
+}
+
+func (r dealRepo) Stats(_ context.Context, dr repository.DateRange) (repository.DealStats, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	st := repository.DealStats{ByStage: map[string]int64{}, AmountByStage: map[string]int64{}}
+	var terms int64
+	for _, d := range alive(r.s.data.deals, func(d dealRow) bool { return inRange(d.ClosedAt, dr) }) {
+		st.Count++
+		st.TotalAmountIDR += d.AmountIDR
+		terms += int64(d.TermMonths)
+		st.ByStage[d.Stage]++
+		st.AmountByStage[d.Stage] += d.AmountIDR
+	}
+	if st.Count > 0 {
+		st.AvgTermMonths = float64(terms) / float64(st.Count)
+	}
+	return st, nil
+}
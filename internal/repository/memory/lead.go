@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type leadRow models.Lead
+
+func (r leadRow) key() uint          { return r.LeadID }
+func (r leadRow) created() time.Time { return r.CreatedAt }
+func (r leadRow) deletedAt() (time.Time, bool) {
+	return r.DeletedAt.Time, r.DeletedAt.Valid
+}
+
+func (r leadRow) column(name string) any {
+	switch name {
+	case "lead_id":
+		return r.LeadID
+	case "created_at":
+		return r.CreatedAt
+	case "company_name":
+		return r.CompanyName
+	case "contact_name":
+		return r.ContactName
+	case "email":
+		return r.Email
+	case "phone":
+		return r.Phone
+	case "source":
+		return r.Source
+	case "industry":
+		return r.Industry
+	case "region":
+		return r.Region
+	case "sales_rep":
+		return r.SalesRep
+	case "status":
+		return r.Status
+	case "notes":
+		return r.Notes
+	}
+	return nil
+}
+
+type leadRepo struct{ s *Store }
+
+func (r leadRepo) Create(_ context.Context, l *models.Lead) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	l.LeadID = r.s.data.next("leads")
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = r.s.now()
+	}
+	_ = l.BeforeCreate(nil)
+	row := *l
+	row.Deals = nil
+	r.s.data.leads[l.LeadID] = leadRow(row)
+	return nil
+}
+
+func (r leadRepo) Get(_ context.Context, id uint) (models.Lead, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.leads[id]
+	if !ok || row.DeletedAt.Valid {
+		return models.Lead{}, repository.ErrNotFound
+	}
+	return models.Lead(row), nil
+}
+
+func (r leadRepo) List(_ context.Context, f repository.LeadFilter, p repository.Page) ([]models.Lead, repository.PageInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := alive(r.s.data.leads, func(l leadRow) bool {
+		if f.Q != "" && !contains(&l.CompanyName, f.Q) && !contains(&l.ContactName, f.Q) && !contains(&l.Email, f.Q) {
+			return false
+		}
+		return matches(l, f.Where)
+	})
+	items, info := keyset(rows, f.Query, p)
+	return leads(items), info, nil
+}
+
+func (r leadRepo) UpdateIfVersion(_ context.Context, l *models.Lead, version uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cur, ok := r.s.data.leads[l.LeadID]
+	if !ok || cur.DeletedAt.Valid || cur.Version != version {
+		return false, nil
+	}
+	row := *l
+	row.CreatedAt, row.DeletedAt, row.Deals = cur.CreatedAt, cur.DeletedAt, nil
+	r.s.data.leads[l.LeadID] = leadRow(row)
+	return true, nil
+}
+
+func (r leadRepo) SoftDelete(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.leads[id]
+	if !ok || row.DeletedAt.Valid {
+		return false, nil
+	}
+	row.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	r.s.data.leads[id] = row
+	return true, nil
+}
+
+func (r leadRepo) GetTrashed(_ context.Context, id uint) (models.Lead, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.leads[id]
+	if !ok || !row.DeletedAt.Valid {
+		return models.Lead{}, repository.ErrNotFound
+	}
+	return models.Lead(row), nil
+}
+
+func (r leadRepo) ListTrashed(_ context.Context, p repository.Page) ([]models.Lead, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	items, total := paginate(trashed(r.s.data.leads), p)
+	return leads(items), total, nil
+}
+
+func (r leadRepo) Restore(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if row, ok := r.s.data.leads[id]; ok {
+		row.DeletedAt = gorm.DeletedAt{}
+		r.s.data.leads[id] = row
+	}
+	return nil
+}
+
+func (r leadRepo) Count(_ context.Context, dr repository.DateRange) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(alive(r.s.data.leads, func(l leadRow) bool { return inRange(l.CreatedAt, dr) }))), nil
+}
+
+func (r leadRepo) CountBy(_ context.Context, column string, dr repository.DateRange) (map[string]int64, error) {
+	var field func(leadRow) *string
+	switch column {
+	case "status":
+		field = func(l leadRow) *string { return l.Status }
+	case "source":
+		field = func(l leadRow) *string { return l.Source }
+	case "region":
+		field = func(l leadRow) *string { return l.Region }
+	default:
+		return nil, fmt.Errorf("cannot group leads by %q", column)
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	out := map[string]int64{}
+	for _, l := range alive(r.s.data.leads, func(l leadRow) bool { return inRange(l.CreatedAt, dr) }) {
+		k := ""
+		if v := field(l); v != nil {
+			k = *v
+		}
+		out[k]++
+	}
+	return out, nil
+}
+
+func (r leadRepo) ReassignSalesRep(_ context.Context, from, to string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, l := range r.s.data.leads {
+		if eq(l.SalesRep, from) {
+			name := to
+			l.SalesRep = &name
+			l.Version++
+			r.s.data.leads[id] = l
+		}
+	}
+	return nil
+}
+
+func leads(rows []leadRow) []models.Lead {
+	out := make([]models.Lead, len(rows))
+	for i, r := range rows {
+		out[i] = models.Lead(r)
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type resetRow models.PasswordReset
+
+type resetRepo struct{ s *Store }
+
+func (r resetRepo) Create(_ context.Context, pr *models.PasswordReset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	pr.ID = r.s.data.next("password_resets")
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = r.s.now()
+	}
+	row := *pr
+	row.User = models.User{}
+	r.s.data.resets[pr.ID] = resetRow(row)
+	return nil
+}
+
+func (r resetRepo) GetByToken(_ context.Context, token string) (models.PasswordReset, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, pr := range r.s.data.resets {
+		if pr.Token == token {
+			return models.PasswordReset(pr), nil
+		}
+	}
+	return models.PasswordReset{}, repository.ErrNotFound
+}
+
+func (r resetRepo) MarkUsed(_ context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if pr, ok := r.s.data.resets[id]; ok {
+		pr.UsedAt = &at
+		r.s.data.resets[id] = pr
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type projectRow models.Project
+
+func (r projectRow) key() uint          { return r.ID }
+func (r projectRow) created() time.Time { return r.CreatedAt }
+func (r projectRow) deletedAt() (time.Time, bool) {
+	return r.DeletedAt.Time, r.DeletedAt.Valid
+}
+
+func (r projectRow) column(name string) any {
+	switch name {
+	case "id":
+		return r.ID
+	case "name":
+		return r.Name
+	case "description":
+		return r.Description
+	case "status":
+		return r.Status
+	case "owner_user_id":
+		return r.OwnerUserID
+	case "start_date":
+		return r.StartDate
+	case "end_date":
+		return r.EndDate
+	case "created_at":
+		return r.CreatedAt
+	case "updated_at":
+		return r.UpdatedAt
+	}
+	return nil
+}
+
+type projectRepo struct{ s *Store }
+
+func (r projectRepo) Create(_ context.Context, p *models.Project) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := r.s.now()
+	p.ID = r.s.data.next("projects")
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = &now
+	if p.Status == "" {
+		p.Status = "planned"
+	}
+	_ = p.BeforeCreate(nil)
+	row := *p
+	row.Owner = models.User{}
+	r.s.data.projects[p.ID] = projectRow(row)
+	return nil
+}
+
+func (r projectRepo) Get(_ context.Context, id uint) (models.Project, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.projects[id]
+	if !ok || row.DeletedAt.Valid {
+		return models.Project{}, repository.ErrNotFound
+	}
+	return models.Project(row), nil
+}
+
+func (r projectRepo) List(_ context.Context, f repository.ProjectFilter, p repository.Page) ([]models.Project, repository.PageInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := alive(r.s.data.projects, func(p projectRow) bool {
+		if f.Q != "" && !contains(&p.Name, f.Q) && !contains(p.Description, f.Q) {
+			return false
+		}
+		return matches(p, f.Where)
+	})
+	items, info := keyset(rows, f.Query, p)
+	return projects(items), info, nil
+}
+
+func (r projectRepo) UpdateIfVersion(_ context.Context, p *models.Project, version uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cur, ok := r.s.data.projects[p.ID]
+	if !ok || cur.DeletedAt.Valid || cur.Version != version {
+		return false, nil
+	}
+	now := r.s.now()
+	p.UpdatedAt = &now
+	row := *p
+	row.CreatedAt, row.DeletedAt, row.Owner = cur.CreatedAt, cur.DeletedAt, models.User{}
+	r.s.data.projects[p.ID] = projectRow(row)
+	return true, nil
+}
+
+func (r projectRepo) SoftDelete(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.projects[id]
+	if !ok || row.DeletedAt.Valid {
+		return false, nil
+	}
+	row.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	r.s.data.projects[id] = row
+	return true, nil
+}
+
+func (r projectRepo) GetTrashed(_ context.Context, id uint) (models.Project, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.projects[id]
+	if !ok || !row.DeletedAt.Valid {
+		return models.Project{}, repository.ErrNotFound
+	}
+	return models.Project(row), nil
+}
+
+func (r projectRepo) ListTrashed(_ context.Context, p repository.Page) ([]models.Project, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	items, total := paginate(trashed(r.s.data.projects), p)
+	return projects(items), total, nil
+}
+
+func (r projectRepo) Restore(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if row, ok := r.s.data.projects[id]; ok {
+		row.DeletedAt = gorm.DeletedAt{}
+		r.s.data.projects[id] = row
+	}
+	return nil
+}
+
+func (r projectRepo) ReassignOwner(_ context.Context, fromUserID, toUserID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, p := range r.s.data.projects {
+		if p.OwnerUserID != nil && *p.OwnerUserID == fromUserID {
+			to := toUserID
+			p.OwnerUserID = &to
+			p.Version++
+			r.s.data.projects[id] = p
+		}
+	}
+	return nil
+}
+
+func projects(rows []projectRow) []models.Project {
+	out := make([]models.Project, len(rows))
+	for i, r := range rows {
+		out[i] = models.Project(r)
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+// listed diimplementasikan baris lead, project dan user: nilai kolom untuk repository.Query.
+type listed interface {
+	row
+	column(name string) any
+}
+
+// matches meniru filter GORM: kolom NULL hanya lolos OpNull, seperti perbandingan SQL.
+func matches[T listed](r T, conds []repository.Cond) bool {
+	for _, c := range conds {
+		v := value(r.column(c.Column))
+		if c.Op == repository.OpNull {
+			if isNull, _ := c.Values[0].(bool); isNull != (v == nil) {
+				return false
+			}
+			continue
+		}
+		if v == nil {
+			return false
+		}
+		n := compare(v, c.Values[0])
+		var ok bool
+		switch c.Op {
+		case repository.OpEq:
+			ok = n == 0
+		case repository.OpNe:
+			ok = n != 0
+		case repository.OpIn:
+			ok = slices.ContainsFunc(c.Values, func(want any) bool { return compare(v, want) == 0 })
+		case repository.OpLike:
+			s, _ := v.(string)
+			ok = contains(&s, c.Values[0].(string))
+		case repository.OpGt:
+			ok = n > 0
+		case repository.OpGte:
+			ok = n >= 0
+		case repository.OpLt:
+			ok = n < 0
+		case repository.OpLte:
+			ok = n <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// keyset meniru keysetPage GORM di atas rows dari alive (sudah urut created_at DESC, id DESC).
+func keyset[T listed](rows []T, q repository.Query, p repository.Page) ([]T, repository.PageInfo) {
+	var info repository.PageInfo
+	if p.WithTotal {
+		total := int64(len(rows))
+		info.Total = &total
+	}
+	desc, ok := q.Keyset()
+	if !ok {
+		sortBy(rows, q.Sort)
+		items, _ := paginate(rows, p)
+		return items, info
+	}
+	if !desc {
+		slices.Reverse(rows)
+	}
+	// pos < 0 kalau r ada sebelum c di urutan list, 0 kalau r baris c itu sendiri.
+	pos := func(r T, c *repository.Cursor) int {
+		n := r.created().Compare(c.CreatedAt)
+		if n == 0 {
+			n = cmp.Compare(r.key(), c.ID)
+		}
+		if desc {
+			return -n
+		}
+		return n
+	}
+	start, end := 0, len(rows)
+	switch {
+	case p.After != nil:
+		for start < end && pos(rows[start], p.After) <= 0 {
+			start++
+		}
+	case p.Before != nil:
+		end = 0
+		for end < len(rows) && pos(rows[end], p.Before) < 0 {
+			end++
+		}
+		start = max(end-p.PerPage, 0)
+	default:
+		start = min(p.Offset(), end)
+	}
+	var more bool
+	if p.Before != nil {
+		more = start > 0
+	} else {
+		more = end-start > p.PerPage
+		end = min(end, start+p.PerPage)
+	}
+	page := rows[start:end]
+	if len(page) == 0 {
+		return []T{}, info
+	}
+	first, last := cursorOf(page[0]), cursorOf(page[len(page)-1])
+	switch {
+	case p.Before != nil:
+		info.Next = &last
+		if more {
+			info.Prev = &first
+		}
+	default:
+		if more {
+			info.Next = &last
+		}
+		if p.After != nil || p.Page > 1 {
+			info.Prev = &first
+		}
+	}
+	return page, info
+}
+
+func cursorOf[T row](r T) repository.Cursor {
+	return repository.Cursor{CreatedAt: r.created(), ID: r.key()}
+}
+
+// sortBy mengurutkan rows per kunci sort, lalu id searah kunci terakhir. NULL di depan untuk ASC
+// (seperti MySQL dan SQLite).
+func sortBy[T listed](rows []T, sorts []repository.Sort) {
+	slices.SortStableFunc(rows, func(a, b T) int {
+		for _, s := range sorts {
+			va, vb := value(a.column(s.Column)), value(b.column(s.Column))
+			var n int
+			switch {
+			case va == nil && vb == nil:
+			case va == nil:
+				n = -1
+			case vb == nil:
+				n = 1
+			default:
+				n = compare(va, vb)
+			}
+			if s.Desc {
+				n = -n
+			}
+			if n != 0 {
+				return n
+			}
+		}
+		n := cmp.Compare(a.key(), b.key())
+		if sorts[len(sorts)-1].Desc {
+			return -n
+		}
+		return n
+	})
+}
+
+// value menyeragamkan nilai kolom ke tipe nilai repository.Cond (string, int64, time.Time); nil untuk NULL.
+func value(v any) any {
+	switch v := v.(type) {
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case uint:
+		return int64(v)
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		return int64(*v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return v
+}
+
+// compare membandingkan dua nilai bertipe sama; tipe berbeda dianggap tidak sama.
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmp.Compare(a, b)
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return -1
+}
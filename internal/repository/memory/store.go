@@ -0,0 +1,199 @@
+// Package memory berisi fake in-memory untuk repository.Store, dipakai unit test service/handler
+// tanpa database. Perilakunya mengikuti implementasi GORM (soft delete, version check, unique email),
+// tapi transaksinya hanya snapshot-rollback tanpa isolasi antar goroutine.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type state struct {
+	leads    map[uint]leadRow
+	deals    map[uint]dealRow
+	projects map[uint]projectRow
+	users    map[uint]userRow
+	resets   map[uint]resetRow
+	audit    map[uint]auditRow
+	seq      map[string]uint
+}
+
+func (s *state) clone() *state {
+	return &state{
+		leads:    maps.Clone(s.leads),
+		deals:    maps.Clone(s.deals),
+		projects: maps.Clone(s.projects),
+		users:    maps.Clone(s.users),
+		resets:   maps.Clone(s.resets),
+		audit:    maps.Clone(s.audit),
+		seq:      maps.Clone(s.seq),
+	}
+}
+
+func (s *state) next(table string) uint {
+	s.seq[table]++
+	return s.seq[table]
+}
+
+// Store implementasi repository.Store yang menyimpan data di map.
+type Store struct {
+	mu   sync.Mutex
+	data *state
+	now  func() time.Time
+}
+
+var _ repository.Store = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{
+		data: &state{
+			leads:    map[uint]leadRow{},
+			deals:    map[uint]dealRow{},
+			projects: map[uint]projectRow{},
+			users:    map[uint]userRow{},
+			resets:   map[uint]resetRow{},
+			audit:    map[uint]auditRow{},
+			seq:      map[string]uint{},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *Store) Leads() repository.LeadRepository                   { return leadRepo{s} }
+func (s *Store) Deals() repository.DealRepository                   { return dealRepo{s} }
+func (s *Store) Projects() repository.ProjectRepository             { return projectRepo{s} }
+func (s *Store) Users() repository.UserRepository                   { return userRepo{s} }
+func (s *Store) PasswordResets() repository.PasswordResetRepository { return resetRepo{s} }
+func (s *Store) Audit() repository.AuditRepository                  { return auditRepo{s} }
+
+// Transaction mengembalikan semua data ke kondisi sebelum fn kalau fn mengembalikan error.
+func (s *Store) Transaction(_ context.Context, fn func(tx repository.Store) error) error {
+	s.mu.Lock()
+	snapshot := s.data.clone()
+	s.mu.Unlock()
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *Store) PurgeTrash(_ context.Context, before time.Time) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[string]int64{}
+	out["deals"] = purge(s.data.deals, before)
+	out["leads"] = purge(s.data.leads, before)
+	out["projects"] = purge(s.data.projects, before)
+	for id, u := range s.data.users {
+		if !u.DeletedAt.Valid || !u.DeletedAt.Time.Before(before) {
+			continue
+		}
+		for pid, p := range s.data.projects {
+			if p.OwnerUserID != nil && *p.OwnerUserID == id {
+				p.OwnerUserID = nil
+				s.data.projects[pid] = p
+			}
+		}
+		for rid, r := range s.data.resets {
+			if r.UserID == id {
+				delete(s.data.resets, rid)
+			}
+		}
+	}
+	out["users"] = purge(s.data.users, before)
+	return out, nil
+}
+
+// row diimplementasikan tipe baris di map supaya helper generik bisa membaca id, created_at dan deleted_at.
+type row interface {
+	key() uint
+	created() time.Time
+	deletedAt() (time.Time, bool)
+}
+
+func purge[T row](m map[uint]T, before time.Time) int64 {
+	var n int64
+	for id, r := range m {
+		if at, ok := r.deletedAt(); ok && at.Before(before) {
+			delete(m, id)
+			n++
+		}
+	}
+	return n
+}
+
+// alive baris yang belum dihapus dan lolos filter, urut created_at DESC (id DESC kalau sama).
+func alive[T row](m map[uint]T, keep func(T) bool) []T {
+	var out []T
+	for _, r := range m {
+		if _, deleted := r.deletedAt(); deleted || (keep != nil && !keep(r)) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].created().Equal(out[j].created()) {
+			return out[i].created().After(out[j].created())
+		}
+		return out[i].key() > out[j].key()
+	})
+	return out
+}
+
+// trashed baris soft-deleted, terbaru dihapus duluan.
+func trashed[T row](m map[uint]T) []T {
+	var out []T
+	for _, r := range m {
+		if _, deleted := r.deletedAt(); deleted {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, _ := out[i].deletedAt()
+		b, _ := out[j].deletedAt()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return out[i].key() > out[j].key()
+	})
+	return out
+}
+
+func paginate[T any](items []T, p repository.Page) ([]T, int64) {
+	total := int64(len(items))
+	start := p.Offset()
+	if start >= len(items) {
+		return []T{}, total
+	}
+	end := start + p.PerPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], total
+}
+
+// contains meniru LIKE '%q%' dengan collation case-insensitive.
+func contains(v *string, q string) bool {
+	return v != nil && strings.Contains(strings.ToLower(*v), strings.ToLower(q))
+}
+
+func eq(v *string, want string) bool { return v != nil && *v == want }
+
+func inRange(t time.Time, r repository.DateRange) bool {
+	if r.From != nil && t.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.Before(*r.To) {
+		return false
+	}
+	return true
+}
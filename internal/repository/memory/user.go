@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type userRow models.User
+
+func (r userRow) key() uint          { return r.ID }
+func (r userRow) created() time.Time { return r.CreatedAt }
+func (r userRow) deletedAt() (time.Time, bool) {
+	return r.DeletedAt.Time, r.DeletedAt.Valid
+}
+
+func (r userRow) column(name string) any {
+	switch name {
+	case "id":
+		return r.ID
+	case "name":
+		return r.Name
+	case "email":
+		return r.Email
+	case "role":
+		return r.Role
+	case "status":
+		return r.Status
+	case "created_at":
+		return r.CreatedAt
+	case "updated_at":
+		return r.UpdatedAt
+	}
+	return nil
+}
+
+type userRepo struct{ s *Store }
+
+// emailTaken meniru unique index users.email, yang hanya berlaku untuk user di luar trash.
+func (r userRepo) emailTaken(email string, exceptID uint) bool {
+	for id, u := range r.s.data.users {
+		if id != exceptID && !u.DeletedAt.Valid && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r userRepo) Create(_ context.Context, u *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.emailTaken(u.Email, 0) {
+		return repository.ErrDuplicate
+	}
+	now := r.s.now()
+	u.ID = r.s.data.next("users")
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	u.UpdatedAt = &now
+	if u.Role == "" {
+		u.Role = "user"
+	}
+	if u.Status == "" {
+		u.Status = models.UserStatusActive
+	}
+	_ = u.BeforeCreate(nil)
+	r.s.data.users[u.ID] = userRow(*u)
+	return nil
+}
+
+func (r userRepo) Get(_ context.Context, id uint) (models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.users[id]
+	if !ok || row.DeletedAt.Valid {
+		return models.User{}, repository.ErrNotFound
+	}
+	return models.User(row), nil
+}
+
+func (r userRepo) GetByEmail(_ context.Context, email string) (models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, u := range r.s.data.users {
+		if !u.DeletedAt.Valid && strings.EqualFold(u.Email, email) {
+			return models.User(u), nil
+		}
+	}
+	return models.User{}, repository.ErrNotFound
+}
+
+func (r userRepo) List(_ context.Context, f repository.UserFilter, p repository.Page) ([]models.User, repository.PageInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := alive(r.s.data.users, func(u userRow) bool {
+		return (f.Q == "" || contains(&u.Name, f.Q) || contains(&u.Email, f.Q)) && matches(u, f.Where)
+	})
+	items, info := keyset(rows, f.Query, p)
+	return users(items), info, nil
+}
+
+func (r userRepo) UpdateIfVersion(_ context.Context, u *models.User, version uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cur, ok := r.s.data.users[u.ID]
+	if !ok || cur.DeletedAt.Valid || cur.Version != version {
+		return false, nil
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return false, repository.ErrDuplicate
+	}
+	now := r.s.now()
+	u.UpdatedAt = &now
+	row := *u
+	row.CreatedAt, row.DeletedAt = cur.CreatedAt, cur.DeletedAt
+	row.PasswordHash, row.TokenVersion, row.Status = cur.PasswordHash, cur.TokenVersion, cur.Status
+	r.s.data.users[u.ID] = userRow(row)
+	return true, nil
+}
+
+// update menjalankan fn pada user aktif dengan id tersebut (tidak ada apa-apa kalau tidak ditemukan).
+func (r userRepo) update(id uint, fn func(*userRow)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if u, ok := r.s.data.users[id]; ok && !u.DeletedAt.Valid {
+		fn(&u)
+		r.s.data.users[id] = u
+	}
+	return nil
+}
+
+func (r userRepo) SetStatus(_ context.Context, id uint, status string) error {
+	return r.update(id, func(u *userRow) {
+		u.Status = status
+		u.TokenVersion++
+		u.Version++
+	})
+}
+
+func (r userRepo) UpdatePassword(_ context.Context, id uint, hash string) error {
+	return r.update(id, func(u *userRow) {
+		u.PasswordHash = hash
+		u.TokenVersion++
+	})
+}
+
+func (r userRepo) RevokeTokens(_ context.Context, id uint) error {
+	return r.update(id, func(u *userRow) { u.TokenVersion++ })
+}
+
+func (r userRepo) CountActiveAdmins(_ context.Context, excludeID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(alive(r.s.data.users, func(u userRow) bool {
+		return u.ID != excludeID && u.Role == "admin" && u.Status == models.UserStatusActive
+	}))
+	return int64(n), nil
+}
+
+func (r userRepo) CountByName(_ context.Context, name string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, u := range r.s.data.users {
+		if u.Name == name {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r userRepo) SoftDelete(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.users[id]
+	if !ok || row.DeletedAt.Valid {
+		return false, nil
+	}
+	row.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	r.s.data.users[id] = row
+	return true, nil
+}
+
+func (r userRepo) GetTrashed(_ context.Context, id uint) (models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.data.users[id]
+	if !ok || !row.DeletedAt.Valid {
+		return models.User{}, repository.ErrNotFound
+	}
+	return models.User(row), nil
+}
+
+func (r userRepo) ListTrashed(_ context.Context, p repository.Page) ([]models.User, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	items, total := paginate(trashed(r.s.data.users), p)
+	return users(items), total, nil
+}
+
+func (r userRepo) Restore(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if row, ok := r.s.data.users[id]; ok {
+		if row.DeletedAt.Valid && r.emailTaken(row.Email, id) {
+			return repository.ErrDuplicate
+		}
+		row.DeletedAt = gorm.DeletedAt{}
+		r.s.data.users[id] = row
+	}
+	return nil
+}
+
+func users(rows []userRow) []models.User {
+	out := make([]models.User, len(rows))
+	for i, r := range rows {
+		out[i] = models.User(r)
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package repository
+
+import "slices"
+
+// Op operator filter list, ditulis di query string sebagai field[op]=nilai (tanpa [op] berarti eq).
+type Op string
+
+const (
+	OpEq   Op = "eq"
+	OpNe   Op = "ne"
+	OpIn   Op = "in"   // nilai dipisah koma
+	OpLike Op = "like" // mengandung, seperti q
+	OpGt   Op = "gt"
+	OpGte  Op = "gte"
+	OpLt   Op = "lt"
+	OpLte  Op = "lte"
+	OpNull Op = "null" // true: IS NULL, false: IS NOT NULL
+)
+
+// Kind tipe nilai field; menentukan parsing nilai dan operator yang boleh dipakai.
+type Kind int
+
+const (
+	KindString Kind = iota // nilai string
+	KindInt                // nilai int64
+	KindTime               // nilai time.Time
+)
+
+// Ops operator yang didukung Kind ini. OpNull hanya untuk field yang Nullable.
+func (k Kind) Ops() []Op {
+	switch k {
+	case KindInt:
+		return []Op{OpEq, OpNe, OpIn, OpGt, OpGte, OpLt, OpLte}
+	case KindTime:
+		return []Op{OpGt, OpGte, OpLt, OpLte}
+	default:
+		return []Op{OpEq, OpNe, OpIn, OpLike}
+	}
+}
+
+// Field satu field list yang boleh difilter dan (kalau Sortable) dipakai di sort. Name nama JSON dan nama
+// parameter query, Column nama kolom di DB. Hanya kolom dari daftar ini yang pernah masuk ke SQL.
+type Field struct {
+	Name     string
+	Column   string
+	Kind     Kind
+	Nullable bool
+	Sortable bool
+}
+
+// Allows true kalau op boleh dipakai pada field ini.
+func (f Field) Allows(op Op) bool {
+	return op == OpNull && f.Nullable || slices.Contains(f.Kind.Ops(), op)
+}
+
+// Cond satu kondisi filter. Values berisi satu nilai (banyak untuk OpIn, bool untuk OpNull) dengan tipe
+// sesuai Kind field: string, int64 atau time.Time.
+type Cond struct {
+	Column string
+	Op     Op
+	Values []any
+}
+
+// Sort satu kunci urutan.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Query filter dan urutan list hasil parse query string. Sort kosong berarti created_at DESC.
+type Query struct {
+	Where []Cond
+	Sort  []Sort
+}
+
+// Keyset true kalau list bisa dipaginasi dengan cursor (created_at, id): urutan default atau hanya
+// created_at. desc arah urutan created_at.
+func (q Query) Keyset() (desc, ok bool) {
+	switch {
+	case len(q.Sort) == 0:
+		return true, true
+	case len(q.Sort) == 1 && q.Sort[0].Column == "created_at":
+		return q.Sort[0].Desc, true
+	}
+	return false, false
+}
+
+var LeadFields = []Field{
+	{Name: "id", Column: "lead_id", Kind: KindInt, Sortable: true},
+	{Name: "created_at", Column: "created_at", Kind: KindTime, Sortable: true},
+	{Name: "company_name", Column: "company_name", Sortable: true},
+	{Name: "contact_name", Column: "contact_name", Sortable: true},
+	{Name: "email", Column: "email", Sortable: true},
+	{Name: "phone", Column: "phone", Nullable: true},
+	{Name: "source", Column: "source", Nullable: true, Sortable: true},
+	{Name: "industry", Column: "industry", Nullable: true, Sortable: true},
+	{Name: "region", Column: "region", Nullable: true, Sortable: true},
+	{Name: "sales_rep", Column: "sales_rep", Nullable: true, Sortable: true},
+	{Name: "status", Column: "status", Nullable: true, Sortable: true},
+	{Name: "notes", Column: "notes", Nullable: true},
+}
+
+var ProjectFields = []Field{
+	{Name: "id", Column: "id", Kind: KindInt, Sortable: true},
+	{Name: "name", Column: "name", Sortable: true},
+	{Name: "description", Column: "description", Nullable: true},
+	{Name: "status", Column: "status", Sortable: true},
+	{Name: "owner_user_id", Column: "owner_user_id", Kind: KindInt, Nullable: true, Sortable: true},
+	{Name: "start_date", Column: "start_date", Kind: KindTime, Nullable: true, Sortable: true},
+	{Name: "end_date", Column: "end_date", Kind: KindTime, Nullable: true, Sortable: true},
+	{Name: "created_at", Column: "created_at", Kind: KindTime, Sortable: true},
+	{Name: "updated_at", Column: "updated_at", Kind: KindTime, Nullable: true, Sortable: true},
+}
+
+var UserFields = []Field{
+	{Name: "id", Column: "id", Kind: KindInt, Sortable: true},
+	{Name: "name", Column: "name", Sortable: true},
+	{Name: "email", Column: "email", Sortable: true},
+	{Name: "role", Column: "role", Sortable: true},
+	{Name: "status", Column: "status", Sortable: true},
+	{Name: "created_at", Column: "created_at", Kind: KindTime, Sortable: true},
+	{Name: "updated_at", Column: "updated_at", Kind: KindTime, Nullable: true, Sortable: true},
+}
+
+var AuditFields = []Field{
+	{Name: "id", Column: "id", Kind: KindInt, Sortable: true},
+	{Name: "created_at", Column: "created_at", Kind: KindTime, Sortable: true},
+	{Name: "actor_user_id", Column: "actor_user_id", Kind: KindInt, Nullable: true},
+	{Name: "action", Column: "action"},
+	{Name: "entity_type", Column: "entity_type"},
+	{Name: "entity_id", Column: "entity_id"},
+	{Name: "ip", Column: "ip"},
+	{Name: "request_id", Column: "request_id"},
+}
@@ -0,0 +1,165 @@
+// Package repository berisi akses data untuk leads, deals, projects, users dan audit log.
+// Implementasi GORM ada di package ini, fake in-memory untuk unit test ada di repository/memory.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("duplicate key")
+)
+
+// Page parameter pagination. Page dimulai dari 1 dan hanya dipakai kalau After dan Before kosong.
+type Page struct {
+	Page    int
+	PerPage int
+	After   *Cursor // halaman berikutnya: baris sesudah cursor di urutan list
+	Before  *Cursor // halaman sebelumnya: baris sebelum cursor
+	// WithTotal menjalankan COUNT(*); tanpa ini PageInfo.Total nil.
+	WithTotal bool
+}
+
+func (p Page) Offset() int { return (p.Page - 1) * p.PerPage }
+
+// Cursor posisi satu baris di urutan keyset created_at DESC, id DESC.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// PageInfo hasil pagination keyset. Next/Prev nil kalau tidak ada halaman ke arah itu.
+type PageInfo struct {
+	Total *int64
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+// DateRange rentang waktu [From, To). Nil berarti tidak dibatasi.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// LeadFilter, ProjectFilter dan UserFilter: Query memakai field dari LeadFields, ProjectFields dan UserFields.
+type LeadFilter struct {
+	Q string // cari di company_name, contact_name, email
+	Query
+}
+
+type ProjectFilter struct {
+	Q string // cari di name, description
+	Query
+}
+
+type UserFilter struct {
+	Q string // cari di name, email
+	Query
+}
+
+// AuditFilter filter audit log: Query memakai field dari AuditFields, Created membatasi created_at.
+type AuditFilter struct {
+	Query
+	Created DateRange
+}
+
+// DealStats agregat deal berdasarkan closed_at.
+type DealStats struct {
+	Count          int64
+	TotalAmountIDR int64
+	AvgTermMonths  float64
+	ByStage        map[string]int64 // jumlah deal per stage
+	AmountByStage  map[string]int64 // total amount_idr per stage
+}
+
+type LeadRepository interface {
+	Create(ctx context.Context, l *models.Lead) error
+	Get(ctx context.Context, id uint) (models.Lead, error)
+	List(ctx context.Context, f LeadFilter, p Page) ([]models.Lead, PageInfo, error)
+	// UpdateIfVersion menulis semua kolom l hanya kalau version di DB masih `version`.
+	// false berarti sudah diubah request lain di antara read dan write.
+	UpdateIfVersion(ctx context.Context, l *models.Lead, version uint) (bool, error)
+	SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error)
+	GetTrashed(ctx context.Context, id uint) (models.Lead, error)
+	ListTrashed(ctx context.Context, p Page) ([]models.Lead, int64, error)
+	Restore(ctx context.Context, id uint) error
+	Count(ctx context.Context, r DateRange) (int64, error)
+	// CountBy jumlah lead per nilai kolom (status, source atau region). NULL dikelompokkan sebagai "".
+	CountBy(ctx context.Context, column string, r DateRange) (map[string]int64, error)
+	ReassignSalesRep(ctx context.Context, from, to string) error
+}
+
+type DealRepository interface {
+	Create(ctx context.Context, d *models.Deal) error
+	SoftDeleteByLead(ctx context.Context, leadID uint, at time.Time) error
+	// RestoreByLead hanya mengembalikan deal yang ikut terhapus bersama lead (deleted_at sama).
+	RestoreByLead(ctx context.Context, leadID uint, deletedAt time.Time) error
+	Stats(ctx context.Context, r DateRange) (DealStats, error)
+}
+
+type ProjectRepository interface {
+	Create(ctx context.Context, p *models.Project) error
+	Get(ctx context.Context, id uint) (models.Project, error)
+	List(ctx context.Context, f ProjectFilter, p Page) ([]models.Project, PageInfo, error)
+	UpdateIfVersion(ctx context.Context, p *models.Project, version uint) (bool, error)
+	SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error)
+	GetTrashed(ctx context.Context, id uint) (models.Project, error)
+	ListTrashed(ctx context.Context, p Page) ([]models.Project, int64, error)
+	Restore(ctx context.Context, id uint) error
+	ReassignOwner(ctx context.Context, fromUserID, toUserID uint) error
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, u *models.User) error
+	Get(ctx context.Context, id uint) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	List(ctx context.Context, f UserFilter, p Page) ([]models.User, PageInfo, error)
+	// UpdateIfVersion tidak menyentuh password_hash, token_version dan status;
+	// kolom itu hanya diubah lewat method khusus di bawah.
+	UpdateIfVersion(ctx context.Context, u *models.User, version uint) (bool, error)
+	// SetStatus juga menaikkan token_version supaya semua token lama langsung invalid.
+	SetStatus(ctx context.Context, id uint, status string) error
+	UpdatePassword(ctx context.Context, id uint, hash string) error
+	RevokeTokens(ctx context.Context, id uint) error
+	// CountActiveAdmins jumlah admin aktif selain excludeID. Baris admin aktif dikunci sampai transaksi
+	// selesai, jadi panggil di dalam Store.Transaction bersama write yang bergantung pada hasilnya.
+	CountActiveAdmins(ctx context.Context, excludeID uint) (int64, error)
+	// CountByName jumlah user dengan nama tersebut, termasuk yang ada di trash.
+	CountByName(ctx context.Context, name string) (int64, error)
+	SoftDelete(ctx context.Context, id uint, at time.Time) (bool, error)
+	GetTrashed(ctx context.Context, id uint) (models.User, error)
+	ListTrashed(ctx context.Context, p Page) ([]models.User, int64, error)
+	// Restore mengembalikan ErrDuplicate kalau email user sudah dipakai user lain di luar trash.
+	Restore(ctx context.Context, id uint) error
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, pr *models.PasswordReset) error
+	GetByToken(ctx context.Context, token string) (models.PasswordReset, error)
+	MarkUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, a *models.AuditLog) error
+	List(ctx context.Context, f AuditFilter, p Page) ([]models.AuditLog, PageInfo, error)
+}
+
+// Store mengumpulkan semua repository supaya service bisa menjalankan beberapa operasi dalam satu transaksi.
+type Store interface {
+	Leads() LeadRepository
+	Deals() DealRepository
+	Projects() ProjectRepository
+	Users() UserRepository
+	PasswordResets() PasswordResetRepository
+	Audit() AuditRepository
+
+	// Transaction menjalankan fn dengan Store yang terikat ke satu transaksi; error dari fn me-rollback semuanya.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+	// PurgeTrash menghapus permanen baris soft-deleted yang deleted_at-nya sebelum `before`, per tabel.
+	PurgeTrash(ctx context.Context, before time.Time) (map[string]int64, error)
+}
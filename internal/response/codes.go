@@ -0,0 +1,47 @@
+package response
+
+// Kode error untuk field error.code. Nilainya kontrak dengan frontend: boleh ditambah, jangan diganti.
+const (
+	// Umum, dipakai helper per status kalau handler tidak memberi kode spesifik
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeVersionMismatch      = "VERSION_MISMATCH"
+	CodeIfMatchRequired      = "IF_MATCH_REQUIRED"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInternal             = "INTERNAL_ERROR"
+
+	// Request
+	CodeInvalidJSON      = "INVALID_JSON"
+	CodeInvalidQuery     = "INVALID_QUERY"
+	CodeRouteNotFound    = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodeNotReady         = "NOT_READY"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+
+	// Auth
+	CodeTokenMissing       = "TOKEN_MISSING"
+	CodeTokenInvalid       = "TOKEN_INVALID"
+	CodeTokenRevoked       = "TOKEN_REVOKED"
+	CodeAccountInactive    = "ACCOUNT_INACTIVE"
+	CodeAdminOnly          = "ADMIN_ONLY"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeEmailNotFound      = "EMAIL_NOT_FOUND"
+	CodeResetTokenInvalid  = "RESET_TOKEN_INVALID"
+
+	// Domain
+	CodeLeadNotFound          = "LEAD_NOT_FOUND"
+	CodeLeadNotInTrash        = "LEAD_NOT_IN_TRASH"
+	CodeProjectNotFound       = "PROJECT_NOT_FOUND"
+	CodeProjectNotInTrash     = "PROJECT_NOT_IN_TRASH"
+	CodeUserNotFound          = "USER_NOT_FOUND"
+	CodeUserNotInTrash        = "USER_NOT_IN_TRASH"
+	CodeEmailTaken            = "EMAIL_TAKEN"
+	CodeLastAdmin             = "LAST_ADMIN"
+	CodeInvalidReassignTarget = "INVALID_REASSIGN_TARGET"
+	CodeSalesRepAmbiguous     = "SALES_REP_AMBIGUOUS"
+)
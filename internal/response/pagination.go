@@ -1,9 +1,13 @@
 package response
 
+// Pagination metadata list. Total hanya ada kalau diminta (include_total=true) atau list memakai offset;
+// NextCursor/PrevCursor dikirim balik sebagai ?cursor= untuk halaman berikutnya/sebelumnya.
 type Pagination struct {
-	Page    int   `json:"page"`
-	PerPage int   `json:"per_page"`
-	Total   int64 `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type ListResult struct {
@@ -17,7 +21,7 @@ func List(items interface{}, page, per int, total int64) ListResult {
 		Pagination: Pagination{
 			Page:    page,
 			PerPage: per,
-			Total:   total,
+			Total:   &total,
 		},
 	}
 }
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/i18n"
+)
+
+const MIMEProblem = "application/problem+json"
+
+// Problem body error format RFC 7807, dipakai kalau header Accept meminta application/problem+json.
+// Type selalu about:blank sehingga Title = teks status HTTP (diterjemahkan); pesan spesifik ada di Detail.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+	Data      interface{}  `json:"data,omitempty"`
+}
+
+func wantsProblem(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), MIMEProblem)
+}
+
+func writeProblem(c *gin.Context, status int, e ErrorBody, message string, data interface{}) {
+	c.Render(status, problemRender{Problem{
+		Type:      "about:blank",
+		Title:     i18n.T(Lang(c), http.StatusText(status)),
+		Status:    status,
+		Detail:    message,
+		Instance:  c.Request.URL.Path,
+		Code:      e.Code,
+		Errors:    e.Fields,
+		RequestID: e.RequestID,
+		TraceID:   e.TraceID,
+		Data:      data,
+	}})
+}
+
+// problemRender render.JSON dengan content type application/problem+json.
+type problemRender struct{ p Problem }
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	body, err := json.Marshal(r.p)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header()["Content-Type"] = []string{MIMEProblem}
+}
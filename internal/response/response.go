@@ -4,19 +4,57 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/i18n"
+	"github.com/oktaharis/uji-teknis-godigi/internal/tracing"
 )
 
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Error   *ErrorBody  `json:"error,omitempty"`
+}
+
+// ErrorBody detail error yang bisa dibaca mesin; hanya ada kalau success false.
+type ErrorBody struct {
+	Code      string       `json:"code"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"` // sama dengan header X-Request-ID, untuk dicari di log
+	TraceID   string       `json:"trace_id,omitempty"`   // trace OpenTelemetry request ini
 }
 
+// JSON menulis envelope standar. message adalah key katalog i18n (teks bahasa Inggris) dan diterjemahkan
+// sesuai bahasa request.
 func JSON(c *gin.Context, status int, success bool, message string, data interface{}) {
 	c.JSON(status, APIResponse{
 		Success: success,
+		Message: i18n.T(Lang(c), message),
+		Data:    data,
+	})
+}
+
+// Fail response error dengan kode mesin (lihat codes.go). data opsional, mis. representasi terbaru pada 412.
+// Kalau client meminta application/problem+json, dikirim dalam format RFC 7807.
+func Fail(c *gin.Context, status int, code, message string, data interface{}) {
+	fail(c, status, ErrorBody{Code: code}, message, data)
+}
+
+func fail(c *gin.Context, status int, e ErrorBody, message string, data interface{}) {
+	lang := Lang(c)
+	message = i18n.T(lang, message)
+	e.Fields = localize(lang, e.Fields)
+	e.RequestID = c.GetString("request_id") // diisi middleware.RequestID
+	e.TraceID = tracing.TraceID(c.Request.Context())
+	if wantsProblem(c) {
+		writeProblem(c, status, e, message, data)
+		return
+	}
+	c.JSON(status, APIResponse{
+		Success: false,
 		Message: message,
 		Data:    data,
+		Error:   &e,
 	})
 }
 
@@ -33,31 +71,70 @@ func NoContent(c *gin.Context, message string) {
 }
 
 func BadRequest(c *gin.Context, message string, details interface{}) {
-	JSON(c, http.StatusBadRequest, false, orDefault(message, "Bad Request"), details)
+	Fail(c, http.StatusBadRequest, CodeBadRequest, orDefault(message, "Bad Request"), details)
 }
 
 func Unauthorized(c *gin.Context, message string) {
-	JSON(c, http.StatusUnauthorized, false, orDefault(message, "Unauthorized"), nil)
+	Fail(c, http.StatusUnauthorized, CodeUnauthorized, orDefault(message, "Unauthorized"), nil)
 }
 
 func Forbidden(c *gin.Context, message string) {
-	JSON(c, http.StatusForbidden, false, orDefault(message, "Forbidden"), nil)
+	Fail(c, http.StatusForbidden, CodeForbidden, orDefault(message, "Forbidden"), nil)
 }
 
 func NotFound(c *gin.Context, message string) {
-	JSON(c, http.StatusNotFound, false, orDefault(message, "Not Found"), nil)
+	Fail(c, http.StatusNotFound, CodeNotFound, orDefault(message, "Not Found"), nil)
 }
 
 func Conflict(c *gin.Context, message string) {
-	JSON(c, http.StatusConflict, false, orDefault(message, "Conflict"), nil)
+	Fail(c, http.StatusConflict, CodeConflict, orDefault(message, "Conflict"), nil)
+}
+
+func PreconditionFailed(c *gin.Context, message string, current interface{}) {
+	Fail(c, http.StatusPreconditionFailed, CodeVersionMismatch, orDefault(message, "Precondition Failed"), current)
 }
 
-func UnprocessableEntity(c *gin.Context, message string, details interface{}) {
-	JSON(c, http.StatusUnprocessableEntity, false, orDefault(message, "Validation Error"), details)
+func PreconditionRequired(c *gin.Context, message string) {
+	Fail(c, http.StatusPreconditionRequired, CodeIfMatchRequired, orDefault(message, "Precondition Required"), nil)
+}
+
+func UnsupportedMediaType(c *gin.Context, message string) {
+	Fail(c, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, orDefault(message, "Unsupported Media Type"), nil)
+}
+
+// UnprocessableEntity 422 VALIDATION_FAILED dengan daftar field yang gagal (lihat ExtractValidationErrors).
+func UnprocessableEntity(c *gin.Context, message string, fields []FieldError) {
+	fail(c, http.StatusUnprocessableEntity, ErrorBody{Code: CodeValidationFailed, Fields: fields},
+		orDefault(message, "Validation Error"), nil)
+}
+
+// InvalidQuery 400 INVALID_QUERY dengan parameter query yang salah di error.fields (lihat QueryError).
+func InvalidQuery(c *gin.Context, message string, fields []FieldError) {
+	fail(c, http.StatusBadRequest, ErrorBody{Code: CodeInvalidQuery, Fields: fields},
+		orDefault(message, "Invalid query parameters"), nil)
+}
+
+// PayloadTooLarge 413 PAYLOAD_TOO_LARGE untuk body yang melewati MAX_BODY_BYTES.
+func PayloadTooLarge(c *gin.Context, message string) {
+	Fail(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, orDefault(message, "Request body too large"), nil)
+}
+
+// TooManyRequests 429 RATE_LIMITED; header Retry-After diisi pemanggil.
+func TooManyRequests(c *gin.Context, message string) {
+	Fail(c, http.StatusTooManyRequests, CodeRateLimited, orDefault(message, "Too Many Requests"), nil)
 }
 
 func InternalError(c *gin.Context, message string) {
-	JSON(c, http.StatusInternalServerError, false, orDefault(message, "Internal Server Error"), nil)
+	Fail(c, http.StatusInternalServerError, CodeInternal, orDefault(message, "Internal Server Error"), nil)
+}
+
+// Lang bahasa response untuk request ini: hasil middleware i18n kalau terpasang, selain itu langsung
+// dari header Accept-Language.
+func Lang(c *gin.Context) string {
+	if lang := c.GetString(i18n.ContextKey); lang != "" {
+		return lang
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
 }
 
 func orDefault(got, def string) string {
@@ -1,20 +1,162 @@
 package response
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/i18n"
 )
 
-func ExtractValidationErrors(err error) map[string]string {
-	out := map[string]string{}
+// FieldError satu field yang gagal validasi. Field memakai nama JSON (path bertitik untuk nested).
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+
+	// key + args pesan di katalog i18n; Message diterjemahkan ulang sesuai bahasa request.
+	key  string
+	args []interface{}
+}
+
+func fieldError(field, rule, param, key string, args ...interface{}) FieldError {
+	return FieldError{Field: field, Rule: rule, Param: param, Message: i18n.T(i18n.Default, key, args...), key: key, args: args}
+}
+
+// QueryError FieldError untuk parameter query string; key adalah pesan di katalog i18n.
+func QueryError(param, rule, key string, args ...interface{}) FieldError {
+	return fieldError(param, rule, "", key, args...)
+}
+
+// localize menerjemahkan Message ke lang; FieldError tanpa key (dibuat manual) dibiarkan.
+func localize(lang string, fields []FieldError) []FieldError {
+	out := make([]FieldError, len(fields))
+	for i, f := range fields {
+		if f.key != "" {
+			f.Message = i18n.T(lang, f.key, f.args...)
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// ExtractValidationErrors menerjemahkan error validator / decode JSON menjadi daftar FieldError.
+// Nama field mengikuti RegisterTagNameFunc validator-nya (service memakai nama JSON).
+func ExtractValidationErrors(err error) []FieldError {
 	if err == nil {
-		return out
+		return nil
 	}
-	if verrs, ok := err.(validator.ValidationErrors); ok {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		out := make([]FieldError, 0, len(verrs))
 		for _, fe := range verrs {
-			out[fe.Field()] = fe.Tag()
+			key, args := ruleMessage(fe.Tag(), fe.Param(), fe.Kind())
+			out = append(out, fieldError(fieldPath(fe), fe.Tag(), fe.Param(), key, args...))
 		}
 		return out
 	}
-	out["error"] = err.Error()
-	return out
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		typ := jsonType(typeErr.Type)
+		return []FieldError{fieldError(typeErr.Field, "type", typ, "must be "+article(typ))}
+	}
+	if name, ok := unknownField(err); ok {
+		return []FieldError{fieldError(name, "unknown", "", "is not a recognised field")}
+	}
+	return []FieldError{fieldError("", "json", "", "request body must be valid JSON")}
+}
+
+// IsMalformedJSON true kalau body bukan JSON yang valid (bukan sekadar isi yang salah).
+func IsMalformedJSON(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// fieldPath namespace tanpa nama struct teratas, mis. "LeadInput.email" -> "email".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return fe.Field()
+}
+
+// ruleMessage key katalog (dan argumennya) untuk rule validator, tanpa nama field (frontend yang
+// menampilkan label).
+func ruleMessage(rule, param string, kind reflect.Kind) (string, []interface{}) {
+	switch rule {
+	case "required":
+		return "is required", nil
+	case "email":
+		return "must be a valid email address", nil
+	case "min", "gte":
+		return "must be at least %s" + sizeUnit(kind, param), []interface{}{param}
+	case "max", "lte":
+		return "must be at most %s" + sizeUnit(kind, param), []interface{}{param}
+	case "len":
+		return "must be exactly %s" + sizeUnit(kind, param), []interface{}{param}
+	case "oneof":
+		return "must be one of: %s", []interface{}{strings.Join(strings.Fields(param), ", ")}
+	case "url":
+		return "must be a valid URL", nil
+	default:
+		return "is invalid", nil
+	}
+}
+
+func sizeUnit(kind reflect.Kind, n string) string {
+	plural := "s"
+	if n == "1" {
+		plural = ""
+	}
+	switch kind {
+	case reflect.String:
+		return " character" + plural
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " item" + plural
+	}
+	return ""
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return t.Kind().String()
+}
+
+func article(typ string) string {
+	if strings.ContainsRune("aeiou", rune(typ[0])) {
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// unknownField membaca nama field dari error json.Decoder.DisallowUnknownFields (`json: unknown field "x"`).
+func unknownField(err error) (string, bool) {
+	const prefix = `json: unknown field "`
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(msg, prefix), `"`), true
 }
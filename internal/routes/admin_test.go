@@ -0,0 +1,228 @@
+package routes_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// Semua route /admin menolak user biasa (403) dan request tanpa token (401).
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	h := apitest.New(t)
+	user := fmt.Sprintf("/admin/users/%d", h.User.ID)
+
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/admin/users"},
+		{http.MethodGet, "/admin/users"},
+		{http.MethodGet, "/admin/users/trash"},
+		{http.MethodGet, user},
+		{http.MethodPut, user},
+		{http.MethodPatch, user},
+		{http.MethodDelete, user},
+		{http.MethodPost, user + "/suspend"},
+		{http.MethodPost, user + "/deactivate"},
+		{http.MethodPost, user + "/reactivate"},
+		{http.MethodPost, user + "/force-logout"},
+		{http.MethodPost, user + "/reset-password"},
+		{http.MethodPost, user + "/reassign"},
+		{http.MethodPost, user + "/restore"},
+		{http.MethodPost, "/admin/trash/purge"},
+		{http.MethodGet, "/admin/audit"},
+	}
+	var cases []apitest.Case
+	for _, r := range routes {
+		cases = append(cases,
+			apitest.Case{Name: r.method + " " + r.path + " anonymous", Method: r.method, Path: r.path, Want: http.StatusUnauthorized},
+			apitest.Case{Name: r.method + " " + r.path + " user", Method: r.method, Path: r.path, As: apitest.AsUser, Want: http.StatusForbidden,
+				Code: response.CodeAdminOnly},
+		)
+	}
+	h.Run(cases)
+}
+
+func TestAdminUserRoutes(t *testing.T) {
+	h := apitest.New(t)
+	user := fmt.Sprintf("/admin/users/%d", h.User.ID)
+	admin := fmt.Sprintf("/admin/users/%d", h.Admin.ID)
+	mergePatch := func(version int) []string {
+		return append(ifMatch(version), "Content-Type", "application/merge-patch+json")
+	}
+
+	h.Run([]apitest.Case{
+		{Name: "create", Method: http.MethodPost, Path: "/admin/users", As: apitest.AsAdmin,
+			Body: map[string]string{"name": "Wati", "email": "wati@test.local", "password": "secret1", "role": "user"},
+			Want: http.StatusCreated, Check: wantETag(`"1"`)},
+		{Name: "create duplicate email", Method: http.MethodPost, Path: "/admin/users", As: apitest.AsAdmin,
+			Body: map[string]string{"name": "Wati", "email": "wati@test.local", "password": "secret1", "role": "user"},
+			Want: http.StatusConflict},
+		{Name: "create validation", Method: http.MethodPost, Path: "/admin/users", As: apitest.AsAdmin,
+			Body: map[string]string{"name": "Wati", "email": "wati2@test.local", "password": "secret1", "role": "root"},
+			Want: http.StatusUnprocessableEntity},
+
+		{Name: "list", Method: http.MethodGet, Path: "/admin/users?include_total=true", As: apitest.AsAdmin, Want: http.StatusOK, Check: wantTotal(3)},
+		{Name: "list filtered", Method: http.MethodGet, Path: "/admin/users?q=wati&include_total=true", As: apitest.AsAdmin, Want: http.StatusOK,
+			Check: wantTotal(1)},
+
+		{Name: "get", Method: http.MethodGet, Path: user, As: apitest.AsAdmin, Want: http.StatusOK, Check: wantETag(`"1"`)},
+		{Name: "get not found", Method: http.MethodGet, Path: "/admin/users/9999", As: apitest.AsAdmin, Want: http.StatusNotFound},
+
+		{Name: "update without If-Match", Method: http.MethodPut, Path: user, As: apitest.AsAdmin,
+			Body: map[string]string{"name": "Budi S"}, Want: http.StatusPreconditionRequired},
+		{Name: "update validation", Method: http.MethodPut, Path: user, As: apitest.AsAdmin, Header: ifMatch(1),
+			Body: map[string]string{"email": "bukan-email"}, Want: http.StatusUnprocessableEntity},
+		{Name: "update duplicate email", Method: http.MethodPut, Path: user, As: apitest.AsAdmin, Header: ifMatch(1),
+			Body: map[string]string{"email": "wati@test.local"}, Want: http.StatusConflict},
+		{Name: "update", Method: http.MethodPut, Path: user, As: apitest.AsAdmin, Header: ifMatch(1),
+			Body: map[string]string{"name": "Budi Santoso"}, Want: http.StatusOK, Check: wantETag(`"2"`)},
+		{Name: "update stale version", Method: http.MethodPut, Path: user, As: apitest.AsAdmin, Header: ifMatch(1),
+			Body: map[string]string{"name": "Budi"}, Want: http.StatusPreconditionFailed},
+		{Name: "demote last admin", Method: http.MethodPut, Path: admin, As: apitest.AsAdmin, Header: ifMatch(1),
+			Body: map[string]string{"role": "user"}, Want: http.StatusConflict},
+
+		{Name: "patch", Method: http.MethodPatch, Path: user, As: apitest.AsAdmin, Header: mergePatch(2),
+			Body: `{"name":"Budi S"}`, Want: http.StatusOK, Check: wantETag(`"3"`)},
+		{Name: "patch validation", Method: http.MethodPatch, Path: user, As: apitest.AsAdmin, Header: mergePatch(3),
+			Body: `{"role":null}`, Want: http.StatusUnprocessableEntity},
+		{Name: "patch unknown field", Method: http.MethodPatch, Path: user, As: apitest.AsAdmin, Header: mergePatch(3),
+			Body: `{"nickname":"Bud"}`, Want: http.StatusUnprocessableEntity,
+			Check: wantFields(map[string]string{"nickname": "is not a recognised field"})},
+
+		{Name: "delete last admin", Method: http.MethodDelete, Path: admin, As: apitest.AsAdmin, Want: http.StatusConflict,
+			Code: response.CodeLastAdmin},
+		{Name: "delete", Method: http.MethodDelete, Path: user, As: apitest.AsAdmin, Want: http.StatusNoContent},
+		{Name: "delete not found", Method: http.MethodDelete, Path: user, As: apitest.AsAdmin, Want: http.StatusNotFound},
+		{Name: "trash", Method: http.MethodGet, Path: "/admin/users/trash", As: apitest.AsAdmin, Want: http.StatusOK,
+			Check: wantTotal(1)},
+		{Name: "restore", Method: http.MethodPost, Path: user + "/restore", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "restore not in trash", Method: http.MethodPost, Path: user + "/restore", As: apitest.AsAdmin,
+			Want: http.StatusNotFound},
+
+		{Name: "purge trash", Method: http.MethodPost, Path: "/admin/trash/purge", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "audit", Method: http.MethodGet, Path: "/admin/audit?entity_type=user", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "audit filter", Method: http.MethodGet, Path: "/admin/audit?entity_type=user&action[in]=delete,restore&include_total=true",
+			As: apitest.AsAdmin, Want: http.StatusOK, Check: wantTotal(2)},
+		{Name: "audit unknown operator", Method: http.MethodGet, Path: "/admin/audit?action[gt]=a", As: apitest.AsAdmin,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+		{Name: "audit invalid date", Method: http.MethodGet, Path: "/admin/audit?from=kemarin", As: apitest.AsAdmin,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+	})
+}
+
+func TestAdminLifecycleRoutes(t *testing.T) {
+	h := apitest.New(t)
+	user := fmt.Sprintf("/admin/users/%d", h.User.ID)
+	other := h.CreateUser("Wati", "wati@test.local", "user")
+	userToken := h.UserToken()
+
+	h.Run([]apitest.Case{
+		{Name: "suspend last admin", Method: http.MethodPost, Path: fmt.Sprintf("/admin/users/%d/suspend", h.Admin.ID),
+			As: apitest.AsAdmin, Want: http.StatusConflict},
+		{Name: "suspend", Method: http.MethodPost, Path: user + "/suspend", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "suspended token rejected", Method: http.MethodGet, Path: "/me", Token: userToken, Want: http.StatusUnauthorized},
+		{Name: "suspend not found", Method: http.MethodPost, Path: "/admin/users/9999/suspend", As: apitest.AsAdmin,
+			Want: http.StatusNotFound},
+		{Name: "reactivate", Method: http.MethodPost, Path: user + "/reactivate", As: apitest.AsAdmin, Want: http.StatusOK},
+
+		{Name: "reassign validation", Method: http.MethodPost, Path: user + "/reassign", As: apitest.AsAdmin,
+			Body: map[string]int{}, Want: http.StatusUnprocessableEntity,
+			Check: wantFields(map[string]string{"to_user_id": "is required"})},
+		{Name: "reassign to self", Method: http.MethodPost, Path: user + "/reassign", As: apitest.AsAdmin,
+			Body: map[string]uint{"to_user_id": h.User.ID}, Want: http.StatusBadRequest, Code: response.CodeInvalidReassignTarget},
+		{Name: "reassign", Method: http.MethodPost, Path: user + "/reassign", As: apitest.AsAdmin,
+			Body: map[string]uint{"to_user_id": other.ID}, Want: http.StatusOK},
+
+		{Name: "deactivate invalid target", Method: http.MethodPost, Path: user + "/deactivate", As: apitest.AsAdmin,
+			Body: map[string]int{"reassign_to": 9999}, Want: http.StatusBadRequest},
+		{Name: "deactivate", Method: http.MethodPost, Path: user + "/deactivate", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "reactivate after deactivate", Method: http.MethodPost, Path: user + "/reactivate", As: apitest.AsAdmin,
+			Want: http.StatusOK},
+
+		{Name: "force logout", Method: http.MethodPost, Path: user + "/force-logout", As: apitest.AsAdmin, Want: http.StatusOK},
+		{Name: "reset password", Method: http.MethodPost, Path: user + "/reset-password", As: apitest.AsAdmin,
+			Want: http.StatusOK},
+		{Name: "reset password not found", Method: http.MethodPost, Path: "/admin/users/9999/reset-password",
+			As: apitest.AsAdmin, Want: http.StatusNotFound},
+	})
+
+	var p models.Project
+	h.DB.First(&p, h.Project.ID)
+	if p.OwnerUserID == nil || *p.OwnerUserID != other.ID {
+		t.Fatalf("project owner = %v, want %d", p.OwnerUserID, other.ID)
+	}
+
+	// Body tanpa Content-Length (chunked) tetap dibaca: target tidak valid ditolak, bukan diabaikan.
+	req := httptest.NewRequest(http.MethodPost, user+"/deactivate", io.MultiReader(strings.NewReader(`{"reassign_to":9999}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+h.AdminToken())
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("deactivate with chunked body = %d, want 400: %s", w.Code, w.Body)
+	}
+}
+
+// Unique index email hanya berlaku di luar trash: email user yang dihapus bisa dipakai register lagi,
+// dan user lama baru bisa di-restore setelah email-nya kosong lagi.
+func TestEmailReusedAfterDelete(t *testing.T) {
+	h := apitest.New(t)
+	old := h.CreateUser("Lama", "lama@test.local", "user")
+	user := fmt.Sprintf("/admin/users/%d", old.ID)
+	register := map[string]string{"name": "Baru", "email": old.Email, "password": "secret1"}
+
+	h.Run([]apitest.Case{
+		{Name: "register while active", Method: http.MethodPost, Path: "/auth/register", Body: register,
+			Want: http.StatusConflict, Code: response.CodeEmailTaken},
+		{Name: "delete", Method: http.MethodDelete, Path: user, As: apitest.AsAdmin, Want: http.StatusNoContent},
+		{Name: "register after delete", Method: http.MethodPost, Path: "/auth/register", Body: register,
+			Want: http.StatusCreated},
+		{Name: "register twice", Method: http.MethodPost, Path: "/auth/register", Body: register,
+			Want: http.StatusConflict, Code: response.CodeEmailTaken},
+		{Name: "admin create", Method: http.MethodPost, Path: "/admin/users", As: apitest.AsAdmin,
+			Body: map[string]string{"name": "Lain", "email": old.Email, "password": "secret1", "role": "user"},
+			Want: http.StatusConflict, Code: response.CodeEmailTaken},
+		{Name: "restore with email in use", Method: http.MethodPost, Path: user + "/restore", As: apitest.AsAdmin,
+			Want: http.StatusConflict, Code: response.CodeEmailTaken},
+	})
+	h.Login(old.Email, "secret1")
+}
+
+// Audit ditulis di transaksi yang sama dengan perubahannya: actor dan request id dari request, dan kalau
+// audit_log tidak bisa ditulis, request gagal tanpa meninggalkan perubahan.
+func TestAuditWrittenWithChange(t *testing.T) {
+	h := apitest.New(t)
+	token := h.UserToken()
+	lead := fmt.Sprintf("/leads/%d", h.Lead.LeadID)
+
+	res := h.Do(http.MethodDelete, lead, token, nil, "X-Request-ID", "req-audit-1")
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d: %s", res.Code, res.Raw)
+	}
+	var row models.AuditLog
+	if err := h.DB.Where("action = ? AND entity_type = ?", "delete", "lead").First(&row).Error; err != nil {
+		t.Fatalf("audit row for delete: %v", err)
+	}
+	if row.RequestID != "req-audit-1" || row.ActorUserID == nil || *row.ActorUserID != h.User.ID ||
+		row.EntityID != fmt.Sprint(h.Lead.LeadID) {
+		t.Fatalf("audit row = %+v", row)
+	}
+
+	if err := h.DB.Exec("ALTER TABLE audit_log RENAME TO audit_log_off").Error; err != nil {
+		t.Fatal(err)
+	}
+	res = h.Do(http.MethodPost, lead+"/restore", token, nil)
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("restore without audit table = %d, want 500", res.Code)
+	}
+	var trashed int64
+	h.DB.Unscoped().Model(&models.Lead{}).Where("lead_id = ? AND deleted_at IS NOT NULL", h.Lead.LeadID).Count(&trashed)
+	if trashed != 1 {
+		t.Fatal("lead restored although its audit row was not written")
+	}
+}
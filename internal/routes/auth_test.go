@@ -0,0 +1,151 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+)
+
+func TestAuthRoutes(t *testing.T) {
+	h := apitest.New(t)
+	var resetToken string
+
+	h.Run([]apitest.Case{
+		{Name: "register", Method: http.MethodPost, Path: "/auth/register",
+			Body: map[string]string{"name": "Rina", "email": "rina@test.local", "password": "secret1"},
+			Want: http.StatusCreated},
+		{Name: "register duplicate email", Method: http.MethodPost, Path: "/auth/register",
+			Body: map[string]string{"name": "Rina", "email": "rina@test.local", "password": "secret1"},
+			Want: http.StatusConflict, Code: response.CodeEmailTaken},
+		{Name: "register validation", Method: http.MethodPost, Path: "/auth/register",
+			Body: map[string]string{"name": "R", "email": "not-an-email", "password": "123"},
+			Want: http.StatusUnprocessableEntity, Code: response.CodeValidationFailed,
+			Check: wantFields(map[string]string{
+				"name":     "must be at least 2 characters",
+				"email":    "must be a valid email address",
+				"password": "must be at least 6 characters",
+			})},
+		{Name: "register wrong type", Method: http.MethodPost, Path: "/auth/register",
+			Body: `{"name":"Rina","email":"rina2@test.local","password":123456}`,
+			Want: http.StatusUnprocessableEntity, Check: wantFields(map[string]string{"password": "must be a string"})},
+		{Name: "register malformed json", Method: http.MethodPost, Path: "/auth/register",
+			Body: "{", Want: http.StatusUnprocessableEntity, Code: response.CodeInvalidJSON},
+
+		{Name: "login", Method: http.MethodPost, Path: "/auth/login",
+			Body: map[string]string{"email": "rina@test.local", "password": "secret1"},
+			Want: http.StatusOK, Check: func(t *testing.T, r *apitest.Response) {
+				var data struct {
+					Token string `json:"token"`
+				}
+				r.Decode(&data)
+				if data.Token == "" {
+					t.Fatal("login returned empty token")
+				}
+			}},
+		{Name: "login wrong password", Method: http.MethodPost, Path: "/auth/login",
+			Body: map[string]string{"email": "rina@test.local", "password": "wrong-password"},
+			Want: http.StatusUnauthorized, Code: response.CodeInvalidCredentials},
+		{Name: "login unknown email", Method: http.MethodPost, Path: "/auth/login",
+			Body: map[string]string{"email": "nobody@test.local", "password": "secret1"},
+			Want: http.StatusUnauthorized},
+		{Name: "login validation", Method: http.MethodPost, Path: "/auth/login",
+			Body: map[string]string{"email": "rina"}, Want: http.StatusUnprocessableEntity},
+
+		{Name: "forgot password", Method: http.MethodPost, Path: "/auth/forgot-password",
+			Body: map[string]string{"email": "rina@test.local"},
+			Want: http.StatusOK, Check: func(t *testing.T, r *apitest.Response) {
+				var data struct {
+					ResetToken string `json:"reset_token"`
+				}
+				r.Decode(&data)
+				resetToken = data.ResetToken
+			}},
+		{Name: "forgot password unknown email", Method: http.MethodPost, Path: "/auth/forgot-password",
+			Body: map[string]string{"email": "nobody@test.local"}, Want: http.StatusNotFound, Code: response.CodeEmailNotFound},
+		{Name: "forgot password validation", Method: http.MethodPost, Path: "/auth/forgot-password",
+			Body: map[string]string{}, Want: http.StatusUnprocessableEntity},
+
+		{Name: "reset password invalid token", Method: http.MethodPost, Path: "/auth/reset-password",
+			Body: map[string]string{"token": "nope", "new_password": "secret2"}, Want: http.StatusBadRequest,
+			Code: response.CodeResetTokenInvalid},
+		{Name: "reset password validation", Method: http.MethodPost, Path: "/auth/reset-password",
+			Body: map[string]string{"token": "nope", "new_password": "1"}, Want: http.StatusUnprocessableEntity},
+	})
+
+	t.Run("reset password then login with new password", func(t *testing.T) {
+		r := h.Do(http.MethodPost, "/auth/reset-password", "", map[string]string{"token": resetToken, "new_password": "secret2"})
+		if r.Code != http.StatusOK {
+			t.Fatalf("reset: status %d: %s", r.Code, r.Raw)
+		}
+		if r := h.Do(http.MethodPost, "/auth/reset-password", "", map[string]string{"token": resetToken, "new_password": "secret3"}); r.Code != http.StatusBadRequest {
+			t.Fatalf("reused token: status %d, want 400", r.Code)
+		}
+		h.Login("rina@test.local", "secret2")
+	})
+}
+
+// Token reset hanya keluar di response kalau feature flag menyala dan bukan production.
+func TestResetTokenHidden(t *testing.T) {
+	h := apitest.New(t)
+	adminTok := h.AdminToken()
+	for name, mutate := range map[string]func(*config.Config){
+		"production": func(c *config.Config) { c.AppEnv = "production" },
+		"flag off":   func(c *config.Config) { c.Features.ResetTokenInResponse = false },
+	} {
+		cfg := *h.Cfg
+		mutate(&cfg)
+		h.Router = routes.SetupRouter(&cfg, h.DB, nil)
+		for _, req := range []struct{ path, token string }{
+			{"/auth/forgot-password", ""},
+			{fmt.Sprintf("/admin/users/%d/reset-password", h.User.ID), adminTok},
+		} {
+			r := h.Do(http.MethodPost, req.path, req.token, map[string]string{"email": h.User.Email})
+			if r.Code != http.StatusOK || strings.Contains(string(r.Raw), "reset_token") {
+				t.Errorf("%s: POST %s = %d: %s", name, req.path, r.Code, r.Raw)
+			}
+		}
+	}
+}
+
+func TestSessionRoutes(t *testing.T) {
+	h := apitest.New(t)
+	token := h.UserToken()
+
+	suspended := h.CreateUser("Dodi", "dodi@test.local", "user")
+	suspendedToken := h.LoginAs(suspended)
+	h.DB.Model(&suspended).Update("status", models.UserStatusSuspended)
+
+	h.Run([]apitest.Case{
+		{Name: "me", Method: http.MethodGet, Path: "/me", As: apitest.AsUser, Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var me struct {
+					Email string `json:"email"`
+				}
+				r.Decode(&me)
+				if me.Email != h.User.Email {
+					t.Fatalf("me email = %q, want %q", me.Email, h.User.Email)
+				}
+			}},
+		{Name: "me without token", Method: http.MethodGet, Path: "/me", Want: http.StatusUnauthorized,
+			Code: response.CodeTokenMissing},
+		{Name: "me with malformed token", Method: http.MethodGet, Path: "/me", Token: "not-a-jwt", Want: http.StatusUnauthorized,
+			Code: response.CodeTokenInvalid},
+		{Name: "me suspended account", Method: http.MethodGet, Path: "/me", Token: suspendedToken, Want: http.StatusForbidden,
+			Code: response.CodeAccountInactive},
+		{Name: "login suspended account", Method: http.MethodPost, Path: "/auth/login",
+			Body: map[string]string{"email": suspended.Email, "password": apitest.Password}, Want: http.StatusForbidden},
+		{Name: "whoami", Method: http.MethodGet, Path: "/debug/whoami", As: apitest.AsUser, Want: http.StatusOK},
+		{Name: "whoami without token", Method: http.MethodGet, Path: "/debug/whoami", Want: http.StatusUnauthorized},
+
+		{Name: "logout without token", Method: http.MethodPost, Path: "/auth/logout", Want: http.StatusUnauthorized},
+		{Name: "logout", Method: http.MethodPost, Path: "/auth/logout", Token: token, Want: http.StatusNoContent},
+		{Name: "token revoked after logout", Method: http.MethodGet, Path: "/me", Token: token, Want: http.StatusUnauthorized},
+	})
+}
@@ -0,0 +1,103 @@
+package routes_test
+
+import (
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/buildinfo"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// wantChecks mendecode health.Report dari data lalu membandingkan status tiap check.
+func wantChecks(status string, checks map[string]string) func(t *testing.T, r *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		t.Helper()
+		var rep health.Report
+		r.Decode(&rep)
+		if rep.Status != status {
+			t.Fatalf("status = %q, want %q: %s", rep.Status, status, r.Raw)
+		}
+		if len(rep.Checks) != len(checks) {
+			t.Fatalf("checks = %+v, want %v", rep.Checks, checks)
+		}
+		for name, want := range checks {
+			got, ok := rep.Checks[name]
+			if !ok || got.Status != want || got.LatencyMS < 0 {
+				t.Errorf("check %s = %+v, want status %s", name, got, want)
+			}
+			if want == health.StatusFail && got.Error == "" {
+				t.Errorf("failed check %s has no error", name)
+			}
+		}
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	h := apitest.New(t)
+	t.Cleanup(func() { health.SetDraining(false) })
+
+	h.Run([]apitest.Case{
+		{Name: "liveness", Method: http.MethodGet, Path: "/healthz", Want: http.StatusOK},
+		{Name: "ready", Method: http.MethodGet, Path: "/readyz", Want: http.StatusOK,
+			Check: wantChecks(health.StatusOK, map[string]string{"database": health.StatusOK, "migrations": health.StatusOK})},
+		{Name: "version", Method: http.MethodGet, Path: "/version", Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var info buildinfo.Info
+				r.Decode(&info)
+				if info.Version != buildinfo.Version || info.GoVersion != runtime.Version() {
+					t.Fatalf("version = %+v", info)
+				}
+			}},
+	})
+
+	// Migration yang lebih baru dari binary ini (dijalankan instance baru saat rolling deploy) tidak membuat gagal,
+	// file migration yang diubah setelah dijalankan membuat gagal.
+	if err := h.DB.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (9999, 'newer', 'x', CURRENT_TIMESTAMP)").Error; err != nil {
+		t.Fatal(err)
+	}
+	h.Run([]apitest.Case{
+		{Name: "newer migration applied", Method: http.MethodGet, Path: "/readyz", Want: http.StatusOK,
+			Check: wantChecks(health.StatusOK, map[string]string{"database": health.StatusOK, "migrations": health.StatusOK})},
+	})
+	if err := h.DB.Exec("DELETE FROM schema_migrations WHERE version = 9999").Error; err != nil {
+		t.Fatal(err)
+	}
+	var checksum string
+	h.DB.Raw("SELECT checksum FROM schema_migrations WHERE version = 1").Scan(&checksum)
+	h.DB.Exec("UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1")
+	h.Run([]apitest.Case{
+		{Name: "checksum mismatch", Method: http.MethodGet, Path: "/readyz", Want: http.StatusServiceUnavailable,
+			Code:  response.CodeNotReady,
+			Check: wantChecks(health.StatusFail, map[string]string{"database": health.StatusOK, "migrations": health.StatusFail})},
+	})
+	h.DB.Exec("UPDATE schema_migrations SET checksum = ? WHERE version = 1", checksum)
+
+	if err := h.DB.Exec("DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)").Error; err != nil {
+		t.Fatal(err)
+	}
+	h.Run([]apitest.Case{
+		{Name: "pending migration", Method: http.MethodGet, Path: "/readyz", Want: http.StatusServiceUnavailable,
+			Code:  response.CodeNotReady,
+			Check: wantChecks(health.StatusFail, map[string]string{"database": health.StatusOK, "migrations": health.StatusFail})},
+	})
+
+	health.SetDraining(true)
+	h.Run([]apitest.Case{
+		{Name: "draining", Method: http.MethodGet, Path: "/readyz", Want: http.StatusServiceUnavailable,
+			Code: response.CodeNotReady, Check: wantChecks(health.StatusDraining, nil)},
+		{Name: "liveness while draining", Method: http.MethodGet, Path: "/healthz", Want: http.StatusOK},
+	})
+	health.SetDraining(false)
+
+	sqlDB, _ := h.DB.DB()
+	sqlDB.Close()
+	h.Run([]apitest.Case{
+		{Name: "database down", Method: http.MethodGet, Path: "/readyz", Want: http.StatusServiceUnavailable,
+			Code:  response.CodeNotReady,
+			Check: wantChecks(health.StatusFail, map[string]string{"database": health.StatusFail, "migrations": health.StatusFail})},
+		{Name: "liveness without database", Method: http.MethodGet, Path: "/healthz", Want: http.StatusOK},
+	})
+}
@@ -0,0 +1,60 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+func TestLocalizedMessages(t *testing.T) {
+	h := apitest.New(t)
+	indonesian := []string{"Accept-Language", "id-ID,id;q=0.9,en;q=0.8"}
+
+	h.Run([]apitest.Case{
+		{Name: "default english", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser,
+			Want: http.StatusNotFound, Check: wantMessage("en", "Lead not found")},
+		{Name: "indonesian", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser, Header: indonesian,
+			Want: http.StatusNotFound, Code: response.CodeLeadNotFound, Check: wantMessage("id", "Lead tidak ditemukan")},
+		{Name: "unsupported language falls back to english", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser,
+			Header: []string{"Accept-Language", "fr-FR,de;q=0.8"}, Want: http.StatusNotFound,
+			Check: wantMessage("en", "Lead not found")},
+		{Name: "success message", Method: http.MethodGet, Path: "/me", As: apitest.AsUser, Header: indonesian,
+			Want: http.StatusOK, Check: wantMessage("id", "Profil")},
+		{Name: "auth middleware", Method: http.MethodGet, Path: "/me", Header: indonesian,
+			Want: http.StatusUnauthorized, Check: wantMessage("id", "Bearer token tidak dikirim")},
+		{Name: "validation fields", Method: http.MethodPost, Path: "/auth/register", Header: indonesian,
+			Body: map[string]string{"name": "Rina", "email": "bukan-email", "password": "123"},
+			Want: http.StatusUnprocessableEntity, Check: func(t *testing.T, r *apitest.Response) {
+				wantMessage("id", "Validasi gagal")(t, r)
+				wantFields(map[string]string{
+					"email":    "harus berupa alamat email yang valid",
+					"password": "minimal 6 karakter",
+				})(t, r)
+			}},
+		{Name: "problem+json", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser,
+			Header: append([]string{"Accept", response.MIMEProblem}, indonesian...), Want: http.StatusNotFound,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var p response.Problem
+				if err := json.Unmarshal(r.Raw, &p); err != nil {
+					t.Fatal(err)
+				}
+				if p.Title != "Tidak ditemukan" || p.Detail != "Lead tidak ditemukan" {
+					t.Fatalf("problem = %+v", p)
+				}
+			}},
+	})
+}
+
+func wantMessage(lang, want string) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		if got := r.Header.Get("Content-Language"); got != lang {
+			t.Errorf("Content-Language = %q, want %q", got, lang)
+		}
+		if r.Message != want {
+			t.Fatalf("message = %q, want %q", r.Message, want)
+		}
+	}
+}
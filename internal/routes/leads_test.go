@@ -0,0 +1,156 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+func TestLeadRoutes(t *testing.T) {
+	h := apitest.New(t)
+	lead := fmt.Sprintf("/leads/%d", h.Lead.LeadID)
+	valid := map[string]string{"company_name": "CV Sentosa", "contact_name": "Agus", "email": "agus@sentosa.co.id"}
+
+	h.Run([]apitest.Case{
+		{Name: "create", Method: http.MethodPost, Path: "/leads", As: apitest.AsUser, Body: valid, Want: http.StatusCreated,
+			Check: wantETag(`"1"`)},
+		{Name: "create validation", Method: http.MethodPost, Path: "/leads", As: apitest.AsUser,
+			Body: map[string]string{"company_name": "CV Sentosa", "email": "bukan-email"}, Want: http.StatusUnprocessableEntity,
+			Check: wantFields(map[string]string{"contact_name": "is required", "email": "must be a valid email address"})},
+		{Name: "create without token", Method: http.MethodPost, Path: "/leads", Body: valid, Want: http.StatusUnauthorized},
+
+		{Name: "list", Method: http.MethodGet, Path: "/leads?per_page=1&include_total=true", As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantTotal(2)},
+		{Name: "list filtered", Method: http.MethodGet, Path: "/leads?q=Sentosa&include_total=true", As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantTotal(1)},
+		{Name: "list without token", Method: http.MethodGet, Path: "/leads", Want: http.StatusUnauthorized},
+
+		{Name: "summary", Method: http.MethodGet, Path: "/leads/summary?from=2000-01-01", As: apitest.AsUser, Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var sum struct {
+					TotalLeads int64 `json:"total_leads"`
+					Deals      struct {
+						Count int64 `json:"count"`
+					} `json:"deals"`
+				}
+				r.Decode(&sum)
+				if sum.TotalLeads != 2 || sum.Deals.Count != 1 {
+					t.Fatalf("summary = %+v, want 2 leads and 1 deal", sum)
+				}
+			}},
+		{Name: "summary without token", Method: http.MethodGet, Path: "/leads/summary", Want: http.StatusUnauthorized},
+
+		{Name: "get", Method: http.MethodGet, Path: lead, As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantETag(`"1"`)},
+		{Name: "get not found", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser, Want: http.StatusNotFound,
+			Code: response.CodeLeadNotFound},
+		{Name: "get not found problem+json", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser,
+			Header: []string{"Accept", response.MIMEProblem}, Want: http.StatusNotFound,
+			Check: func(t *testing.T, r *apitest.Response) {
+				if ct := r.Header.Get("Content-Type"); ct != response.MIMEProblem {
+					t.Fatalf("Content-Type = %s, want %s", ct, response.MIMEProblem)
+				}
+				var p response.Problem
+				if err := json.Unmarshal(r.Raw, &p); err != nil {
+					t.Fatal(err)
+				}
+				if p.Status != http.StatusNotFound || p.Code != response.CodeLeadNotFound || p.Instance != "/leads/9999" {
+					t.Fatalf("problem = %+v", p)
+				}
+			}},
+		{Name: "get non-numeric id", Method: http.MethodGet, Path: "/leads/1%20OR%201=1", As: apitest.AsUser, Want: http.StatusNotFound},
+		{Name: "get without token", Method: http.MethodGet, Path: lead, Want: http.StatusUnauthorized},
+
+		{Name: "update without If-Match", Method: http.MethodPut, Path: lead, As: apitest.AsUser, Body: valid,
+			Want: http.StatusPreconditionRequired, Code: response.CodeIfMatchRequired},
+		{Name: "update validation", Method: http.MethodPut, Path: lead, As: apitest.AsUser, Header: ifMatch(1),
+			Body: map[string]string{"company_name": "PT Maju Jaya"}, Want: http.StatusUnprocessableEntity},
+		{Name: "update", Method: http.MethodPut, Path: lead, As: apitest.AsUser, Header: ifMatch(1),
+			Body: map[string]string{"company_name": "PT Maju Jaya Abadi", "contact_name": "Siti", "email": "siti@majujaya.co.id"},
+			Want: http.StatusOK, Check: wantETag(`"2"`)},
+		{Name: "update stale version", Method: http.MethodPut, Path: lead, As: apitest.AsUser, Header: ifMatch(1), Body: valid,
+			Want: http.StatusPreconditionFailed, Code: response.CodeVersionMismatch},
+		{Name: "update without token", Method: http.MethodPut, Path: lead, Header: ifMatch(2), Body: valid,
+			Want: http.StatusUnauthorized},
+
+		{Name: "patch", Method: http.MethodPatch, Path: lead, As: apitest.AsUser,
+			Header: append(ifMatch(2), "Content-Type", "application/merge-patch+json"),
+			Body:   `{"notes":"follow up minggu depan","status":null}`, Want: http.StatusOK, Check: wantETag(`"3"`)},
+		{Name: "patch validation", Method: http.MethodPatch, Path: lead, As: apitest.AsUser,
+			Header: append(ifMatch(3), "Content-Type", "application/merge-patch+json"),
+			Body:   `{"email":null}`, Want: http.StatusUnprocessableEntity},
+		{Name: "patch wrong content type", Method: http.MethodPatch, Path: lead, As: apitest.AsUser,
+			Header: append(ifMatch(3), "Content-Type", "text/plain"),
+			Body:   `{"notes":"x"}`, Want: http.StatusUnsupportedMediaType},
+		{Name: "patch without token", Method: http.MethodPatch, Path: lead,
+			Header: append(ifMatch(3), "Content-Type", "application/merge-patch+json"),
+			Body:   `{"notes":"x"}`, Want: http.StatusUnauthorized},
+
+		{Name: "delete without token", Method: http.MethodDelete, Path: lead, Want: http.StatusUnauthorized},
+		{Name: "delete", Method: http.MethodDelete, Path: lead, As: apitest.AsUser, Want: http.StatusNoContent},
+		{Name: "get deleted", Method: http.MethodGet, Path: lead, As: apitest.AsUser, Want: http.StatusNotFound},
+		{Name: "delete again", Method: http.MethodDelete, Path: lead, As: apitest.AsUser, Want: http.StatusNotFound},
+
+		{Name: "trash", Method: http.MethodGet, Path: "/leads/trash", As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantTotal(1)},
+		{Name: "trash without token", Method: http.MethodGet, Path: "/leads/trash", Want: http.StatusUnauthorized},
+
+		{Name: "restore without token", Method: http.MethodPost, Path: lead + "/restore", Want: http.StatusUnauthorized},
+		{Name: "restore", Method: http.MethodPost, Path: lead + "/restore", As: apitest.AsUser, Want: http.StatusOK},
+		{Name: "restore not in trash", Method: http.MethodPost, Path: lead + "/restore", As: apitest.AsUser,
+			Want: http.StatusNotFound},
+	})
+
+	var deals int64
+	h.DB.Model(&models.Deal{}).Where("lead_id = ?", h.Lead.LeadID).Count(&deals)
+	if deals != 1 {
+		t.Fatalf("deals after restore = %d, want 1", deals)
+	}
+}
+
+func ifMatch(version int) []string { return []string{"If-Match", fmt.Sprintf(`"%d"`, version)} }
+
+func wantETag(want string) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		if got := r.Header.Get("ETag"); got != want {
+			t.Fatalf("ETag = %s, want %s", got, want)
+		}
+	}
+}
+
+func wantTotal(want int64) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		var list struct {
+			Pagination struct {
+				Total int64 `json:"total"`
+			} `json:"pagination"`
+		}
+		r.Decode(&list)
+		if list.Pagination.Total != want {
+			t.Fatalf("total = %d, want %d", list.Pagination.Total, want)
+		}
+	}
+}
+
+// wantFields memastikan error.fields berisi field -> message yang diharapkan (field lain boleh ada).
+func wantFields(want map[string]string) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		if r.Error == nil {
+			t.Fatalf("missing error body: %s", r.Raw)
+		}
+		got := map[string]string{}
+		for _, f := range r.Error.Fields {
+			got[f.Field] = f.Message
+		}
+		for field, msg := range want {
+			if got[field] != msg {
+				t.Errorf("field %q message = %q, want %q (fields: %+v)", field, got[field], msg, r.Error.Fields)
+			}
+		}
+	}
+}
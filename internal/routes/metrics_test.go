@@ -0,0 +1,107 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/metrics"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	h := apitest.New(t)
+	// Source teks bebas: nilai dikenal dinormalkan, sisanya digabung ke "other".
+	for i, source := range []string{"Cold Call", "cold-call", "Tetangga", "iklan koran"} {
+		l := models.Lead{CompanyName: source, ContactName: "Rudi", Email: fmt.Sprintf("src%d@test.local", i), Source: &source}
+		if err := h.DB.Create(&l).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Stage juga teks bebas: "Lost" dinormalkan, stage lain digabung ke "other".
+	for _, stage := range []string{"Lost", "Negosiasi", "Menunggu PO"} {
+		d := models.Deal{LeadID: h.Lead.LeadID, AmountIDR: 10_000_000, Currency: "IDR", TermMonths: 12, Stage: stage, ClosedAt: time.Now()}
+		if err := h.DB.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Do(http.MethodGet, "/leads/9999", h.UserToken(), nil)
+	h.Do(http.MethodGet, "/leads/1%20OR%201=1", h.UserToken(), nil)
+
+	r := h.Do(http.MethodGet, "/metrics", "", nil)
+	if r.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: status %d", r.Code)
+	}
+	body := string(r.Raw)
+	for _, want := range []string{
+		`godigi_http_requests_total{method="GET",route="/leads/:id",status="404"} 2`,
+		`godigi_http_requests_total{method="POST",route="/auth/login",status="200"} 1`,
+		`godigi_http_request_duration_seconds_bucket{method="GET",route="/leads/:id",status="404",le="+Inf"} 2`,
+		`godigi_db_query_duration_seconds_count{operation="query",table="users"}`,
+		`godigi_db_query_duration_seconds_count{operation="create",table="audit_log"}`,
+		`go_sql_open_connections{db_name="main"}`,
+		`godigi_leads{source="website"} 1`,
+		`godigi_leads{source="cold_call"} 2`,
+		`godigi_leads{source="other"} 2`,
+		`godigi_deals{stage="won"} 1`,
+		`godigi_deals{stage="lost"} 1`,
+		`godigi_deals{stage="other"} 2`,
+		`godigi_deal_amount_idr{stage="won"} 5e+07`,
+		`godigi_deal_amount_idr{stage="other"} 2e+07`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+	if strings.Contains(body, "Tetangga") || strings.Contains(body, "Negosiasi") {
+		t.Error("metrics labels contain free-text lead source or deal stage")
+	}
+
+	// Gauge bisnis di-cache: lead baru belum terlihat di scrape berikutnya.
+	source := "Website"
+	if err := h.DB.Create(&models.Lead{CompanyName: "Baru", ContactName: "Rudi", Email: "baru@test.local", Source: &source}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if r := h.Do(http.MethodGet, "/metrics", "", nil); !strings.Contains(string(r.Raw), `godigi_leads{source="website"} 1`) {
+		t.Error("business gauges were queried again instead of served from cache")
+	}
+	if strings.Contains(body, "/leads/9999") {
+		t.Error("metrics labels contain raw path instead of route template")
+	}
+}
+
+func TestMetricsToken(t *testing.T) {
+	r := routes.SetupRouter(&config.Config{MetricsToken: "scrape-secret"}, nil, metrics.New())
+	for _, tc := range []struct {
+		auth string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer scrape-secret", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("Authorization %q: status %d, want %d", tc.auth, w.Code, tc.want)
+		}
+	}
+
+	// Dengan METRICS_ADDR, /metrics tidak ada di router utama.
+	r = routes.SetupRouter(&config.Config{MetricsAddr: ":9090"}, nil, metrics.New())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("/metrics with MetricsAddr: status %d, want 404", w.Code)
+	}
+}
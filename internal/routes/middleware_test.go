@@ -0,0 +1,108 @@
+package routes_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+func TestRouterMiddleware(t *testing.T) {
+	h := apitest.New(t)
+	h.Router.GET("/test/panic", func(c *gin.Context) {
+		_ = c.MustGet("user") // tidak ada auth di route ini -> panic
+	})
+
+	h.Run([]apitest.Case{
+		{Name: "panic returns json", Method: http.MethodGet, Path: "/test/panic", Header: []string{"X-Request-ID", "req-panic-1"},
+			Want: http.StatusInternalServerError, Code: response.CodeInternal, Check: wantRequestID("req-panic-1")},
+		{Name: "unknown route", Method: http.MethodGet, Path: "/nope", Want: http.StatusNotFound,
+			Code: response.CodeRouteNotFound},
+		{Name: "wrong method", Method: http.MethodDelete, Path: "/auth/login", Want: http.StatusMethodNotAllowed,
+			Code: response.CodeMethodNotAllowed, Check: func(t *testing.T, r *apitest.Response) {
+				if allow := r.Header.Get("Allow"); allow != http.MethodPost {
+					t.Fatalf("Allow = %q, want POST", allow)
+				}
+			}},
+		{Name: "request id propagated", Method: http.MethodGet, Path: "/leads/9999", As: apitest.AsUser,
+			Header: []string{"X-Request-ID", "req-abc.123"}, Want: http.StatusNotFound, Check: wantRequestID("req-abc.123")},
+		{Name: "request id on success", Method: http.MethodGet, Path: "/me", As: apitest.AsUser,
+			Header: []string{"X-Request-ID", "req-me"}, Want: http.StatusOK, Check: func(t *testing.T, r *apitest.Response) {
+				if got := r.Header.Get("X-Request-ID"); got != "req-me" {
+					t.Fatalf("X-Request-ID = %q", got)
+				}
+			}},
+		{Name: "request id generated", Method: http.MethodGet, Path: "/nope", Want: http.StatusNotFound,
+			Check: wantRequestID("")},
+		{Name: "unsafe request id replaced", Method: http.MethodGet, Path: "/nope",
+			Header: []string{"X-Request-ID", "bad id\nwith newline"}, Want: http.StatusNotFound, Check: wantRequestID("")},
+	})
+}
+
+// wantRequestID cek header X-Request-ID sama dengan error.request_id. want kosong berarti id harus digenerate.
+func wantRequestID(want string) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		got := r.Header.Get("X-Request-ID")
+		if want != "" && got != want {
+			t.Fatalf("X-Request-ID = %q, want %q", got, want)
+		}
+		if want == "" && len(got) != 32 {
+			t.Fatalf("X-Request-ID = %q, want generated id", got)
+		}
+		if r.Error == nil || r.Error.RequestID != got {
+			t.Fatalf("error = %+v, want request_id %q", r.Error, got)
+		}
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	h := apitest.New(t)
+	h.Router.GET("/test/panic", func(c *gin.Context) { panic("boom") })
+	token := h.UserToken()
+	h.Logs.Reset()
+
+	h.Do(http.MethodGet, "/leads/9999", token, nil, "X-Request-ID", "req-log-1")
+	h.Do(http.MethodGet, "/test/panic", "", nil, "X-Request-ID", "req-log-2")
+	h.Do(http.MethodPost, "/auth/login", "", map[string]string{"email": "budi@test.local", "password": apitest.Password})
+
+	lines := h.LogLines()
+	find := func(msg, requestID string) map[string]interface{} {
+		for _, l := range lines {
+			if l["msg"] == msg && l["request_id"] == requestID {
+				return l
+			}
+		}
+		t.Fatalf("no %q log line for %s in %v", msg, requestID, lines)
+		return nil
+	}
+
+	access := find("request", "req-log-1")
+	want := map[string]interface{}{
+		"level": "WARN", "method": "GET", "route": "/leads/:id", "path": "/leads/9999",
+		"status": float64(404), "user_id": float64(h.User.ID),
+	}
+	for k, v := range want {
+		if access[k] != v {
+			t.Errorf("access log %s = %v, want %v", k, access[k], v)
+		}
+	}
+	if _, ok := access["latency_ms"]; !ok {
+		t.Error("access log without latency_ms")
+	}
+
+	panicLine := find("panic recovered", "req-log-2")
+	if panicLine["panic"] != "boom" || !strings.Contains(panicLine["stack"].(string), "runtime/debug.Stack") {
+		t.Errorf("panic log = %v", panicLine)
+	}
+	if find("request", "req-log-2")["level"] != "ERROR" {
+		t.Error("500 access log should be ERROR")
+	}
+
+	if strings.Contains(h.Logs.String(), apitest.Password) || strings.Contains(h.Logs.String(), token) {
+		t.Error("credentials leaked into logs")
+	}
+}
@@ -0,0 +1,272 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/buildinfo"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/openapi"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+// Bentuk data response yang di handler ditulis sebagai gin.H; hanya dipakai untuk dokumentasi.
+type (
+	registeredUser struct {
+		ID        uint      `json:"id"`
+		Name      string    `json:"name"`
+		Email     string    `json:"email"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	loginToken struct {
+		Token     string    `json:"token"`
+		ExpiresIn int64     `json:"expires_in"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	// resetToken hanya terisi di test mode (features.reset_token_in_response, bukan production)
+	resetToken struct {
+		ResetToken string `json:"reset_token,omitempty"`
+	}
+	profile struct {
+		ID        uint      `json:"id"`
+		Name      string    `json:"name"`
+		Email     string    `json:"email"`
+		Role      string    `json:"role"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	whoami struct {
+		ID    uint   `json:"id"`
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	createdLead struct {
+		ID          uint      `json:"id"`
+		CompanyName string    `json:"company_name"`
+		Status      *string   `json:"status"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+	liveness struct {
+		Status string `json:"status"`
+	}
+	purgeResult struct {
+		Purged map[string]int64 `json:"purged"`
+		Before time.Time        `json:"before"`
+	}
+)
+
+const mergePatch = "application/merge-patch+json"
+
+const mergePatchNote = "Body berupa JSON Merge Patch (RFC 7396) terhadap dokumen ini: key yang tidak dikirim " +
+	"tidak berubah, null mengosongkan field. Hasil merge divalidasi dengan aturan yang sama."
+
+var (
+	pageParams = []openapi.Param{
+		{Name: "page", Type: "integer", Description: "Default 1"},
+		{Name: "per_page", Type: "integer", Description: "Default 10, maksimal 100"},
+	}
+	keysetParams = []openapi.Param{
+		{Name: "cursor", Description: "next_cursor atau prev_cursor dari response sebelumnya"},
+		{Name: "include_total", Type: "boolean", Description: "Hitung pagination.total (COUNT), default false"},
+	}
+	// cursorParams untuk list keyset (created_at DESC, id DESC). page tetap bisa dipakai tanpa cursor.
+	cursorParams = params(pageParams, keysetParams)
+	dateRange    = []openapi.Param{
+		{Name: "from", Format: "date", Description: "YYYY-MM-DD, inklusif"},
+		{Name: "to", Format: "date", Description: "YYYY-MM-DD, inklusif"},
+	}
+)
+
+// filterNote penjelasan query language list yang parameternya dibuat filterParams.
+const filterNote = "Filter: field=nilai, atau field[op]=nilai dengan op ne, in (dipisah koma), like, gt, gte, lt, lte, " +
+	"null (true/false, field nullable). Field waktu hanya gt/gte/lt/lte dengan YYYY-MM-DD (lte/gt mencakup " +
+	"seluruh hari) atau RFC 3339. sort berisi field dipisah koma, awalan - untuk DESC; cursor hanya " +
+	"berlaku untuk urutan created_at. Field atau operator tidak dikenal dijawab 400 INVALID_QUERY."
+
+// filterParams parameter sort dan filter dari whitelist field repository.
+func filterParams(fields []repository.Field) []openapi.Param {
+	var sortable []string
+	var out []openapi.Param
+	for _, f := range fields {
+		if f.Sortable {
+			sortable = append(sortable, f.Name)
+		}
+		ops := f.Kind.Ops()
+		if f.Nullable {
+			ops = append(ops, repository.OpNull)
+		}
+		names := make([]string, len(ops))
+		for i, op := range ops {
+			names[i] = string(op)
+		}
+		desc := "Operator: " + strings.Join(names, ", ")
+		switch f.Kind {
+		case repository.KindTime:
+			out = append(out,
+				openapi.Param{Name: f.Name + "[gte]", Format: "date", Description: desc},
+				openapi.Param{Name: f.Name + "[lte]", Format: "date", Description: desc})
+		case repository.KindInt:
+			out = append(out, openapi.Param{Name: f.Name, Type: "integer", Description: desc})
+		default:
+			out = append(out, openapi.Param{Name: f.Name, Description: desc})
+		}
+	}
+	sortParam := openapi.Param{Name: "sort", Description: "Default -created_at. Field: " + strings.Join(sortable, ", ")}
+	return append([]openapi.Param{sortParam}, out...)
+}
+
+func params(groups ...[]openapi.Param) []openapi.Param {
+	var out []openapi.Param
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+var apiInfo = openapi.Info{
+	Title:   "GoDigi Leads API",
+	Version: "1.0.0",
+	Description: "Semua response memakai envelope APIResponse (success, message, data). " +
+		"Update memakai optimistic locking: kirim ETag dari GET sebagai header If-Match. " +
+		"Bahasa message (en/id) dipilih dari header Accept-Language, default en. " +
+		"Route /auth dan route yang butuh login dibatasi rate limit per user (per IP sebelum login); " +
+		"sisa kuota ada di header RateLimit-Limit, RateLimit-Remaining dan RateLimit-Reset.",
+}
+
+// apiOperations dokumentasi semua route di SetupRouter. TestOpenAPICoversAllRoutes gagal kalau ada
+// route yang belum tercatat di sini.
+var apiOperations = []openapi.Operation{
+	// Operasional
+	{Method: http.MethodGet, Path: "/healthz", Tag: "ops", Summary: "Liveness probe", Response: liveness{}},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "ops", Summary: "Readiness probe",
+		Description: "Ping database dan cek migration sudah lengkap, dengan latency tiap check. " +
+			"503 NOT_READY kalau ada check yang gagal atau server sedang shutdown (draining).",
+		Response: health.Report{}, Errors: []int{http.StatusServiceUnavailable}},
+	{Method: http.MethodGet, Path: "/version", Tag: "ops", Summary: "Info build (versi, commit, waktu build, versi Go)",
+		Response: buildinfo.Info{}},
+
+	// Auth
+	{Method: http.MethodPost, Path: "/auth/register", Tag: "auth", Summary: "Register user baru",
+		Request: service.RegisterInput{}, Response: registeredUser{}, Status: http.StatusCreated,
+		Errors: []int{http.StatusConflict, http.StatusTooManyRequests}},
+	{Method: http.MethodPost, Path: "/auth/login", Tag: "auth", Summary: "Login, mengembalikan JWT",
+		Request: service.LoginInput{}, Response: loginToken{},
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests}},
+	{Method: http.MethodPost, Path: "/auth/forgot-password", Tag: "auth", Summary: "Buat token reset password (test mode)",
+		Request: service.ForgotPasswordInput{}, Response: resetToken{},
+		Errors: []int{http.StatusNotFound, http.StatusTooManyRequests}},
+	{Method: http.MethodPost, Path: "/auth/reset-password", Tag: "auth", Summary: "Ganti password dengan token reset",
+		Request: service.ResetPasswordInput{}, Errors: []int{http.StatusBadRequest, http.StatusTooManyRequests}},
+	{Method: http.MethodPost, Path: "/auth/logout", Tag: "auth", Summary: "Revoke semua token user",
+		Access: openapi.Bearer, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/me", Tag: "auth", Summary: "Profil user yang login",
+		Access: openapi.Bearer, Response: profile{}},
+	{Method: http.MethodGet, Path: "/debug/whoami", Tag: "debug", Summary: "Identitas token",
+		Access: openapi.Bearer, Response: whoami{}},
+
+	// Leads
+	{Method: http.MethodPost, Path: "/leads", Tag: "leads", Summary: "Buat lead",
+		Access: openapi.Bearer, Request: service.LeadInput{}, Response: createdLead{}, Status: http.StatusCreated,
+		ETag: true},
+	{Method: http.MethodGet, Path: "/leads", Tag: "leads", Summary: "Daftar lead",
+		Description: filterNote, Access: openapi.Bearer, Response: models.Lead{}, List: true,
+		Errors: []int{http.StatusBadRequest},
+		Query: params(cursorParams, []openapi.Param{
+			{Name: "q", Description: "Cari di company_name, contact_name, email"},
+		}, filterParams(repository.LeadFields))},
+	{Method: http.MethodGet, Path: "/leads/summary", Tag: "leads", Summary: "Ringkasan lead dan deal",
+		Description: "Tanggal yang tidak valid diabaikan. Lead difilter dengan created_at, deal dengan closed_at.",
+		Access:      openapi.Bearer, Response: service.LeadSummary{}, Query: dateRange},
+	{Method: http.MethodGet, Path: "/leads/trash", Tag: "leads", Summary: "Lead di trash",
+		Access: openapi.Bearer, Response: models.Lead{}, List: true, Query: pageParams},
+	{Method: http.MethodGet, Path: "/leads/:id", Tag: "leads", Summary: "Detail lead",
+		Access: openapi.Bearer, Response: models.Lead{}},
+	{Method: http.MethodPut, Path: "/leads/:id", Tag: "leads", Summary: "Update lead",
+		Access: openapi.Bearer, Request: service.LeadInput{}, Response: models.Lead{}, IfMatch: true},
+	{Method: http.MethodPatch, Path: "/leads/:id", Tag: "leads", Summary: "Update sebagian lead",
+		Description: mergePatchNote, Access: openapi.Bearer, Request: service.LeadInput{}, RequestType: mergePatch,
+		Response: models.Lead{}, IfMatch: true},
+	{Method: http.MethodDelete, Path: "/leads/:id", Tag: "leads", Summary: "Pindahkan lead (dan deals) ke trash",
+		Access: openapi.Bearer, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/leads/:id/restore", Tag: "leads", Summary: "Restore lead beserta deals",
+		Access: openapi.Bearer, Response: models.Lead{}},
+
+	// Projects
+	{Method: http.MethodPost, Path: "/projects", Tag: "projects", Summary: "Buat project",
+		Access: openapi.Bearer, Request: service.ProjectInput{}, Response: models.Project{}, Status: http.StatusCreated,
+		ETag: true},
+	{Method: http.MethodGet, Path: "/projects", Tag: "projects", Summary: "Daftar project",
+		Description: filterNote, Access: openapi.Bearer, Response: models.Project{}, List: true,
+		Errors: []int{http.StatusBadRequest},
+		Query: params(cursorParams, []openapi.Param{
+			{Name: "q", Description: "Cari di name, description"},
+		}, filterParams(repository.ProjectFields))},
+	{Method: http.MethodGet, Path: "/projects/trash", Tag: "projects", Summary: "Project di trash",
+		Access: openapi.Bearer, Response: models.Project{}, List: true, Query: pageParams},
+	{Method: http.MethodGet, Path: "/projects/:id", Tag: "projects", Summary: "Detail project",
+		Access: openapi.Bearer, Response: models.Project{}},
+	{Method: http.MethodPut, Path: "/projects/:id", Tag: "projects", Summary: "Update project",
+		Description: "Field yang tidak dikirim (atau null) tidak berubah.",
+		Access:      openapi.Bearer, Request: service.ProjectInput{}, Response: models.Project{}, IfMatch: true},
+	{Method: http.MethodPatch, Path: "/projects/:id", Tag: "projects", Summary: "Update sebagian project",
+		Description: mergePatchNote, Access: openapi.Bearer, Request: service.ProjectInput{}, RequestType: mergePatch,
+		Response: models.Project{}, IfMatch: true},
+	{Method: http.MethodDelete, Path: "/projects/:id", Tag: "projects", Summary: "Pindahkan project ke trash",
+		Access: openapi.Bearer, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/projects/:id/restore", Tag: "projects", Summary: "Restore project",
+		Access: openapi.Bearer, Response: models.Project{}},
+
+	// Admin: users
+	{Method: http.MethodPost, Path: "/admin/users", Tag: "admin", Summary: "Buat user",
+		Access: openapi.Admin, Request: service.CreateUserInput{}, Response: models.User{}, Status: http.StatusCreated, ETag: true,
+		Errors: []int{http.StatusConflict}},
+	{Method: http.MethodGet, Path: "/admin/users", Tag: "admin", Summary: "Daftar user",
+		Description: filterNote, Access: openapi.Admin, Response: models.User{}, List: true,
+		Errors: []int{http.StatusBadRequest},
+		Query: params(cursorParams, []openapi.Param{{Name: "q", Description: "Cari di name, email"}},
+			filterParams(repository.UserFields))},
+	{Method: http.MethodGet, Path: "/admin/users/trash", Tag: "admin", Summary: "User di trash",
+		Access: openapi.Admin, Response: models.User{}, List: true, Query: pageParams},
+	{Method: http.MethodGet, Path: "/admin/users/:id", Tag: "admin", Summary: "Detail user",
+		Access: openapi.Admin, Response: models.User{}},
+	{Method: http.MethodPut, Path: "/admin/users/:id", Tag: "admin", Summary: "Update user",
+		Description: "Field yang tidak dikirim tidak berubah. Admin aktif terakhir tidak bisa diturunkan jadi user.",
+		Access:      openapi.Admin, Request: service.UpdateUserInput{}, Response: models.User{}, IfMatch: true,
+		Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPatch, Path: "/admin/users/:id", Tag: "admin", Summary: "Update sebagian user",
+		Description: mergePatchNote, Access: openapi.Admin, Request: service.UserDoc{}, RequestType: mergePatch,
+		Response: models.User{}, IfMatch: true, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodDelete, Path: "/admin/users/:id", Tag: "admin", Summary: "Pindahkan user ke trash",
+		Access: openapi.Admin, Status: http.StatusNoContent, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/admin/users/:id/restore", Tag: "admin", Summary: "Restore user",
+		Access: openapi.Admin, Response: models.User{}},
+
+	// Admin: lifecycle user
+	{Method: http.MethodPost, Path: "/admin/users/:id/suspend", Tag: "admin", Summary: "Suspend user",
+		Access: openapi.Admin, Response: models.User{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/admin/users/:id/deactivate", Tag: "admin", Summary: "Nonaktifkan user",
+		Description: "Body opsional; kalau reassign_to diisi, leads dan projects user dipindah ke user tersebut.",
+		Access:      openapi.Admin, Request: service.DeactivateInput{}, OptionalBody: true, Response: models.User{},
+		Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/admin/users/:id/reactivate", Tag: "admin", Summary: "Aktifkan kembali user",
+		Access: openapi.Admin, Response: models.User{}},
+	{Method: http.MethodPost, Path: "/admin/users/:id/force-logout", Tag: "admin", Summary: "Revoke semua sesi user",
+		Access: openapi.Admin},
+	{Method: http.MethodPost, Path: "/admin/users/:id/reset-password", Tag: "admin",
+		Summary: "Revoke sesi dan buat token reset password (test mode)", Access: openapi.Admin, Response: resetToken{}},
+	{Method: http.MethodPost, Path: "/admin/users/:id/reassign", Tag: "admin", Summary: "Pindahkan leads dan projects user",
+		Access: openapi.Admin, Request: service.ReassignInput{}, Errors: []int{http.StatusBadRequest}},
+
+	// Admin: trash & audit
+	{Method: http.MethodPost, Path: "/admin/trash/purge", Tag: "admin",
+		Summary: "Hapus permanen data trash yang lebih tua dari retention", Access: openapi.Admin, Response: purgeResult{}},
+	{Method: http.MethodGet, Path: "/admin/audit", Tag: "admin", Summary: "Audit trail",
+		Description: filterNote, Access: openapi.Admin, Response: models.AuditLog{}, List: true,
+		Errors: []int{http.StatusBadRequest},
+		Query: params([]openapi.Param{
+			{Name: "page", Type: "integer", Description: "Default 1"},
+			{Name: "per_page", Type: "integer", Description: "Default 20, maksimal 100"},
+		}, keysetParams, dateRange, filterParams(repository.AuditFields))},
+}
@@ -0,0 +1,107 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+type projectPage struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	Pagination response.Pagination `json:"pagination"`
+}
+
+func (p projectPage) names() []string {
+	var out []string
+	for _, it := range p.Items {
+		out = append(out, it.Name)
+	}
+	return out
+}
+
+func TestCursorPagination(t *testing.T) {
+	h := apitest.New(t)
+	// Dua pasang created_at kembar supaya urutan id ikut diuji; fixture "Website Revamp" paling baru.
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{base, base, base.Add(time.Hour), base.Add(time.Hour), base.Add(2 * time.Hour)} {
+		p := models.Project{Name: fmt.Sprintf("P%d", i+1), Status: "planned", CreatedAt: at}
+		if err := h.DB.Create(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"Website Revamp", "P5", "P4", "P3", "P2", "P1"}
+	tok := h.UserToken()
+
+	get := func(query string) projectPage {
+		t.Helper()
+		res := h.Do(http.MethodGet, "/projects?per_page=2"+query, tok, nil)
+		if res.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", query, res.Code, res.Raw)
+		}
+		var page projectPage
+		res.Decode(&page)
+		return page
+	}
+
+	var seen []string
+	page := get("")
+	if page.Pagination.Total != nil || page.Pagination.PrevCursor != "" {
+		t.Fatalf("first page pagination = %+v, want no total and no prev_cursor", page.Pagination)
+	}
+	for {
+		seen = append(seen, page.names()...)
+		if page.Pagination.NextCursor == "" {
+			break
+		}
+		page = get("&cursor=" + page.Pagination.NextCursor)
+	}
+	if !slices.Equal(seen, want) {
+		t.Fatalf("forward walk = %v, want %v", seen, want)
+	}
+
+	// Mundur dari halaman terakhir memakai prev_cursor.
+	seen = nil
+	for {
+		seen = append(page.names(), seen...)
+		if page.Pagination.PrevCursor == "" {
+			break
+		}
+		page = get("&cursor=" + page.Pagination.PrevCursor)
+	}
+	if !slices.Equal(seen, want) {
+		t.Fatalf("backward walk = %v, want %v", seen, want)
+	}
+	if page.Pagination.NextCursor == "" {
+		t.Fatal("first page reached backwards should still have next_cursor")
+	}
+
+	if page := get("&include_total=true"); page.Pagination.Total == nil || *page.Pagination.Total != 6 {
+		t.Fatalf("include_total: %+v, want total 6", page.Pagination)
+	}
+	if page := get("&page=2"); !slices.Equal(page.names(), want[2:4]) || page.Pagination.PrevCursor == "" {
+		t.Fatalf("offset page 2 = %v %+v", page.names(), page.Pagination)
+	}
+
+	h.Run([]apitest.Case{
+		{Name: "per_page capped", Method: http.MethodGet, Path: "/projects?per_page=100000", As: apitest.AsUser,
+			Want: http.StatusOK, Check: func(t *testing.T, r *apitest.Response) {
+				var page projectPage
+				r.Decode(&page)
+				if page.Pagination.PerPage != 100 {
+					t.Fatalf("per_page = %d, want 100", page.Pagination.PerPage)
+				}
+			}},
+		{Name: "invalid cursor", Method: http.MethodGet, Path: "/leads?cursor=not-a-cursor", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+		{Name: "invalid cursor users", Method: http.MethodGet, Path: "/admin/users?cursor=e30", As: apitest.AsAdmin,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+	})
+}
@@ -0,0 +1,87 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+)
+
+func TestProjectRoutes(t *testing.T) {
+	h := apitest.New(t)
+	project := fmt.Sprintf("/projects/%d", h.Project.ID)
+	valid := map[string]interface{}{"name": "Mobile App", "status": "in_progress", "start_date": "2024-01-15"}
+	mergePatch := func(version int) []string {
+		return append(ifMatch(version), "Content-Type", "application/merge-patch+json")
+	}
+
+	h.Run([]apitest.Case{
+		{Name: "create", Method: http.MethodPost, Path: "/projects", As: apitest.AsUser, Body: valid, Want: http.StatusCreated,
+			Check: wantETag(`"1"`)},
+		{Name: "create default status", Method: http.MethodPost, Path: "/projects", As: apitest.AsUser,
+			Body: map[string]string{"name": "Intranet"}, Want: http.StatusCreated,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var p struct {
+					Status string `json:"status"`
+				}
+				r.Decode(&p)
+				if p.Status != "planned" {
+					t.Fatalf("status = %q, want planned", p.Status)
+				}
+			}},
+		{Name: "create validation", Method: http.MethodPost, Path: "/projects", As: apitest.AsUser,
+			Body: map[string]string{"name": "X", "status": "unknown"}, Want: http.StatusUnprocessableEntity},
+		{Name: "create without token", Method: http.MethodPost, Path: "/projects", Body: valid, Want: http.StatusUnauthorized},
+
+		{Name: "list", Method: http.MethodGet, Path: "/projects?include_total=true", As: apitest.AsUser, Want: http.StatusOK, Check: wantTotal(3)},
+		{Name: "list filtered", Method: http.MethodGet, Path: "/projects?status=in_progress&include_total=true", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantTotal(1)},
+		{Name: "list without token", Method: http.MethodGet, Path: "/projects", Want: http.StatusUnauthorized},
+
+		{Name: "get", Method: http.MethodGet, Path: project, As: apitest.AsUser, Want: http.StatusOK, Check: wantETag(`"1"`)},
+		{Name: "get not found", Method: http.MethodGet, Path: "/projects/9999", As: apitest.AsUser, Want: http.StatusNotFound},
+		{Name: "get without token", Method: http.MethodGet, Path: project, Want: http.StatusUnauthorized},
+
+		{Name: "update without If-Match", Method: http.MethodPut, Path: project, As: apitest.AsUser, Body: valid,
+			Want: http.StatusPreconditionRequired},
+		{Name: "update validation", Method: http.MethodPut, Path: project, As: apitest.AsUser, Header: ifMatch(1),
+			Body: map[string]string{"status": "in_progress"}, Want: http.StatusUnprocessableEntity},
+		{Name: "update", Method: http.MethodPut, Path: project, As: apitest.AsUser, Header: ifMatch(1),
+			Body: map[string]string{"name": "Website Revamp v2", "description": "fase 2"}, Want: http.StatusOK,
+			Check: wantETag(`"2"`)},
+		{Name: "update stale version", Method: http.MethodPut, Path: project, As: apitest.AsUser, Header: ifMatch(1),
+			Body: valid, Want: http.StatusPreconditionFailed},
+		{Name: "update without token", Method: http.MethodPut, Path: project, Header: ifMatch(2), Body: valid,
+			Want: http.StatusUnauthorized},
+
+		{Name: "patch clears field", Method: http.MethodPatch, Path: project, As: apitest.AsUser, Header: mergePatch(2),
+			Body: `{"description":null}`, Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				var p struct {
+					Description *string `json:"description"`
+				}
+				r.Decode(&p)
+				if p.Description != nil {
+					t.Fatalf("description = %q, want null", *p.Description)
+				}
+			}},
+		{Name: "patch validation", Method: http.MethodPatch, Path: project, As: apitest.AsUser, Header: mergePatch(3),
+			Body: `{"name":null}`, Want: http.StatusUnprocessableEntity},
+		{Name: "patch without token", Method: http.MethodPatch, Path: project, Header: mergePatch(3),
+			Body: `{"name":"x"}`, Want: http.StatusUnauthorized},
+
+		{Name: "delete without token", Method: http.MethodDelete, Path: project, Want: http.StatusUnauthorized},
+		{Name: "delete", Method: http.MethodDelete, Path: project, As: apitest.AsUser, Want: http.StatusNoContent},
+		{Name: "delete not found", Method: http.MethodDelete, Path: project, As: apitest.AsUser, Want: http.StatusNotFound},
+
+		{Name: "trash", Method: http.MethodGet, Path: "/projects/trash", As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantTotal(1)},
+		{Name: "trash without token", Method: http.MethodGet, Path: "/projects/trash", Want: http.StatusUnauthorized},
+
+		{Name: "restore without token", Method: http.MethodPost, Path: project + "/restore", Want: http.StatusUnauthorized},
+		{Name: "restore", Method: http.MethodPost, Path: project + "/restore", As: apitest.AsUser, Want: http.StatusOK},
+		{Name: "restore not in trash", Method: http.MethodPost, Path: project + "/restore", As: apitest.AsUser,
+			Want: http.StatusNotFound},
+	})
+}
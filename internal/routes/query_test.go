@@ -0,0 +1,174 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+)
+
+// wantIDs memastikan items list berisi id persis dengan urutan ini.
+func wantIDs(want ...uint) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		t.Helper()
+		var list struct {
+			Items []struct {
+				ID uint `json:"id"`
+			} `json:"items"`
+		}
+		r.Decode(&list)
+		got := []uint{}
+		for _, it := range list.Items {
+			got = append(got, it.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ids = %v, want %v", got, want)
+		}
+	}
+}
+
+func wantQueryError(param string) func(*testing.T, *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		t.Helper()
+		if r.Error == nil || !slices.ContainsFunc(r.Error.Fields, func(f response.FieldError) bool { return f.Field == param }) {
+			t.Fatalf("want error for %s, got %s", param, r.Raw)
+		}
+	}
+}
+
+func TestLeadQuery(t *testing.T) {
+	h := apitest.New(t)
+	str := func(s string) *string { return &s }
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 10, 0, 0, 0, time.UTC) }
+	leads := []models.Lead{
+		{CompanyName: "CV Alpha", ContactName: "Ani", Email: "ani@alpha.id", Industry: str("IT"), Region: str("Jawa"),
+			SalesRep: str("Okta"), Status: str("New"), CreatedAt: day(1)},
+		{CompanyName: "PT Beta", ContactName: "Bima", Email: "bima@beta.id", Industry: str("Retail"), Region: str("Bali"),
+			SalesRep: str("Okta"), Status: str("Qualified"), CreatedAt: day(2)},
+		{CompanyName: "UD Gamma", ContactName: "Citra", Email: "citra@gamma.id", Industry: str("IT"),
+			Status: str("Lost"), CreatedAt: day(3)},
+	}
+	for i := range leads {
+		if err := h.DB.Create(&leads[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	alpha, beta, gamma := leads[0].LeadID, leads[1].LeadID, leads[2].LeadID
+
+	h.Run([]apitest.Case{
+		{Name: "eq on every column", Method: http.MethodGet, Path: "/leads?industry=IT&sales_rep=Okta", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(alpha)},
+		{Name: "in list", Method: http.MethodGet, Path: "/leads?status[in]=New,Lost", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(gamma, alpha)},
+		{Name: "ne", Method: http.MethodGet, Path: "/leads?industry[ne]=IT", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(beta)},
+		{Name: "is null", Method: http.MethodGet, Path: "/leads?region[null]=true", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(h.Lead.LeadID, gamma)},
+		{Name: "is not null", Method: http.MethodGet, Path: "/leads?region[null]=false&sort=company_name", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(alpha, beta)},
+		{Name: "date range inclusive", Method: http.MethodGet, Path: "/leads?created_at[gte]=2024-03-02&created_at[lte]=2024-03-03",
+			As: apitest.AsUser, Want: http.StatusOK, Check: wantIDs(gamma, beta)},
+		{Name: "like", Method: http.MethodGet, Path: "/leads?email[like]=GAMMA", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(gamma)},
+		{Name: "multi key sort", Method: http.MethodGet, Path: "/leads?industry[null]=false&sort=industry,-created_at",
+			As: apitest.AsUser, Want: http.StatusOK, Check: wantIDs(gamma, alpha, beta)},
+		{Name: "ascending created_at keeps cursor", Method: http.MethodGet, Path: "/leads?sort=created_at&per_page=1",
+			As: apitest.AsUser, Want: http.StatusOK, Check: func(t *testing.T, r *apitest.Response) {
+				wantIDs(alpha)(t, r)
+				var list projectPage
+				r.Decode(&list)
+				if list.Pagination.NextCursor == "" {
+					t.Fatal("sort=created_at should still return next_cursor")
+				}
+			}},
+		{Name: "value is bound not spliced", Method: http.MethodGet, Path: "/leads?company_name=x'%20OR%20'1'='1",
+			As: apitest.AsUser, Want: http.StatusOK, Check: wantIDs()},
+
+		{Name: "unknown sort field", Method: http.MethodGet, Path: "/leads?sort=-created_at,company_name%3BDROP%20TABLE%20leads",
+			As: apitest.AsUser, Want: http.StatusBadRequest, Code: response.CodeInvalidQuery, Check: wantQueryError("sort")},
+		{Name: "notes not sortable", Method: http.MethodGet, Path: "/leads?sort=notes", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+		{Name: "unknown field with operator", Method: http.MethodGet, Path: "/leads?password[eq]=x", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery, Check: wantQueryError("password[eq]")},
+		{Name: "operator not allowed", Method: http.MethodGet, Path: "/leads?company_name[null]=true", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery, Check: wantQueryError("company_name[null]")},
+		{Name: "invalid date", Method: http.MethodGet, Path: "/leads?created_at[gte]=kemarin", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery, Check: wantQueryError("created_at[gte]")},
+		{Name: "cursor with custom sort", Method: http.MethodGet, Path: "/leads?sort=company_name&cursor=abc", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+		{Name: "unrelated params ignored", Method: http.MethodGet, Path: "/leads?_=123&industry=Retail", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(beta)},
+	})
+
+	res := h.Do(http.MethodGet, "/leads?sort=bogus", h.UserToken(), nil, "Accept-Language", "id")
+	if res.Error == nil || len(res.Error.Fields) != 1 || res.Error.Fields[0].Message != "bogus bukan field yang bisa diurutkan" {
+		t.Fatalf("localized query error: %s", res.Raw)
+	}
+}
+
+// %, _ dan \ di parameter pencarian dicari apa adanya, bukan sebagai wildcard LIKE.
+func TestLikeEscaping(t *testing.T) {
+	h := apitest.New(t)
+	leads := []models.Lead{
+		{CompanyName: "PT Diskon 100% Jaya", ContactName: "Dina", Email: "promo_50@diskon.id"},
+		{CompanyName: "PT Diskon 1000 Jaya", ContactName: "Dani", Email: "promox50@diskon.id"},
+		{CompanyName: `CV Back\slash`, ContactName: "Bagas", Email: "bagas@back.id"},
+	}
+	for i := range leads {
+		if err := h.DB.Create(&leads[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	diskon, backslash := leads[0].LeadID, leads[2].LeadID
+
+	h.Run([]apitest.Case{
+		{Name: "q with percent", Method: http.MethodGet, Path: "/leads?q=100%25", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(diskon)},
+		{Name: "q with underscore", Method: http.MethodGet, Path: "/leads?q=promo_50", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(diskon)},
+		{Name: "q with backslash", Method: http.MethodGet, Path: "/leads?q=k%5Cs", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(backslash)},
+		{Name: "like filter with percent and underscore", Method: http.MethodGet, Path: "/leads?email[like]=o_50@", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(diskon)},
+		{Name: "like filter only wildcards", Method: http.MethodGet, Path: "/leads?company_name[like]=%25", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(diskon)},
+		{Name: "user search with underscore", Method: http.MethodGet, Path: "/admin/users?q=_", As: apitest.AsAdmin,
+			Want: http.StatusOK, Check: wantIDs()},
+	})
+}
+
+func TestProjectAndUserQuery(t *testing.T) {
+	h := apitest.New(t)
+	other := h.CreateUser("Wati", "wati@test.local", "user")
+	mine := h.CreateProject("CRM", &other.ID)
+	orphan := models.Project{Name: "Tanpa Owner", Status: "in_progress"}
+	if err := h.DB.Create(&orphan).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	h.Run([]apitest.Case{
+		{Name: "project by owner", Method: http.MethodGet, Path: "/projects?owner_user_id=" + fmt.Sprint(other.ID), As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(mine.ID)},
+		{Name: "project without owner", Method: http.MethodGet, Path: "/projects?owner_user_id[null]=true", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(orphan.ID)},
+		{Name: "project status in", Method: http.MethodGet, Path: "/projects?status[in]=in_progress,done", As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(orphan.ID)},
+		{Name: "project created today", Method: http.MethodGet,
+			Path: "/projects?sort=name&created_at[gte]=" + time.Now().UTC().Format(time.DateOnly), As: apitest.AsUser,
+			Want: http.StatusOK, Check: wantIDs(mine.ID, orphan.ID, h.Project.ID)},
+		{Name: "project owner must be integer", Method: http.MethodGet, Path: "/projects?owner_user_id=budi", As: apitest.AsUser,
+			Want: http.StatusBadRequest, Code: response.CodeInvalidQuery, Check: wantQueryError("owner_user_id")},
+
+		{Name: "users by role", Method: http.MethodGet, Path: "/admin/users?role=admin", As: apitest.AsAdmin,
+			Want: http.StatusOK, Check: wantIDs(h.Admin.ID)},
+		{Name: "users sorted by name", Method: http.MethodGet, Path: "/admin/users?role[in]=user,admin&sort=-name",
+			As: apitest.AsAdmin, Want: http.StatusOK, Check: wantIDs(other.ID, h.User.ID, h.Admin.ID)},
+		{Name: "password hash not filterable", Method: http.MethodGet, Path: "/admin/users?password_hash[like]=a",
+			As: apitest.AsAdmin, Want: http.StatusBadRequest, Code: response.CodeInvalidQuery},
+	})
+}
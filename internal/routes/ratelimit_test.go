@@ -0,0 +1,98 @@
+package routes_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+)
+
+// wantRateHeaders cek header RateLimit-* dan, kalau ditolak, Retry-After.
+func wantRateHeaders(limit, remaining int) func(t *testing.T, r *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		t.Helper()
+		if got := r.Header.Get("RateLimit-Limit"); got != strconv.Itoa(limit) {
+			t.Errorf("RateLimit-Limit = %q, want %d", got, limit)
+		}
+		if got := r.Header.Get("RateLimit-Remaining"); got != strconv.Itoa(remaining) {
+			t.Errorf("RateLimit-Remaining = %q, want %d", got, remaining)
+		}
+		if reset, err := strconv.Atoi(r.Header.Get("RateLimit-Reset")); err != nil || reset < 1 || reset > 60 {
+			t.Errorf("RateLimit-Reset = %q, want 1..60", r.Header.Get("RateLimit-Reset"))
+		}
+		retry := r.Header.Get("Retry-After")
+		if r.Code == http.StatusTooManyRequests {
+			if n, err := strconv.Atoi(retry); err != nil || n < 1 {
+				t.Errorf("Retry-After = %q, want seconds", retry)
+			}
+		} else if retry != "" {
+			t.Errorf("Retry-After = %q on an allowed request", retry)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	h := apitest.New(t)
+	userTok, adminTok := h.UserToken(), h.AdminToken() // login sebelum limiter dipasang
+	reportTok := h.LoginAs(h.CreateUser("Rani", "rani@test.local", "user"))
+
+	cfg := *h.Cfg
+	cfg.RateLimit = config.RateLimit{
+		Enabled: true,
+		Auth:    config.Rate{Requests: 2, Per: time.Minute},
+		Read:    config.Rate{Requests: 2, Per: time.Minute},
+		Write:   config.Rate{Requests: 1, Per: time.Minute},
+		Export:  config.Rate{Requests: 1, Per: time.Minute},
+	}
+	h.Router = routes.SetupRouter(&cfg, h.DB, nil)
+
+	login := map[string]string{"email": h.User.Email, "password": "wrong-password"}
+	h.Run([]apitest.Case{
+		// Auth: per IP, login gagal tetap memakai kuota
+		{Name: "login 1", Method: http.MethodPost, Path: "/auth/login", Body: login, Want: http.StatusUnauthorized,
+			Check: wantRateHeaders(2, 1)},
+		{Name: "login 2", Method: http.MethodPost, Path: "/auth/login", Body: login, Want: http.StatusUnauthorized,
+			Check: wantRateHeaders(2, 0)},
+		{Name: "login 3 limited", Method: http.MethodPost, Path: "/auth/login", Body: login,
+			Want: http.StatusTooManyRequests, Code: response.CodeRateLimited, Check: wantRateHeaders(2, 0)},
+
+		// Read dan write bucket terpisah, per user
+		{Name: "user read 1", Method: http.MethodGet, Path: "/leads", Token: userTok, Want: http.StatusOK,
+			Check: wantRateHeaders(2, 1)},
+		{Name: "user read 2", Method: http.MethodGet, Path: "/leads", Token: userTok, Want: http.StatusOK},
+		{Name: "user read 3 limited", Method: http.MethodGet, Path: "/leads/summary", Token: userTok,
+			Want: http.StatusTooManyRequests, Code: response.CodeRateLimited},
+		{Name: "user write still allowed", Method: http.MethodPost, Path: "/projects", Token: userTok,
+			Body: map[string]string{"name": "Rate Limited", "status": "planned"}, Want: http.StatusCreated,
+			Check: wantRateHeaders(1, 0)},
+		{Name: "user write limited", Method: http.MethodPost, Path: "/projects", Token: userTok,
+			Body: map[string]string{"name": "Rate Limited 2", "status": "planned"}, Want: http.StatusTooManyRequests},
+		{Name: "other user has own bucket", Method: http.MethodGet, Path: "/leads", Token: adminTok, Want: http.StatusOK,
+			Check: wantRateHeaders(2, 1)},
+
+		// Summary memakai bucket read dan bucket export; yang kedua ditolak export (limit 1) walau read
+		// (limit 2) masih punya token
+		{Name: "summary 1", Method: http.MethodGet, Path: "/leads/summary", Token: reportTok, Want: http.StatusOK,
+			Check: wantRateHeaders(1, 0)},
+		{Name: "summary 2 export limited", Method: http.MethodGet, Path: "/leads/summary", Token: reportTok,
+			Want: http.StatusTooManyRequests, Code: response.CodeRateLimited, Check: wantRateHeaders(1, 0)},
+
+		// Probe tidak dibatasi
+		{Name: "healthz not limited", Method: http.MethodGet, Path: "/healthz", Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				if r.Header.Get("RateLimit-Limit") != "" {
+					t.Fatal("healthz should not be rate limited")
+				}
+			}},
+	})
+
+	res := h.Do(http.MethodGet, "/leads", userTok, nil, "Accept-Language", "id")
+	if res.Code != http.StatusTooManyRequests || res.Message != "Batas jumlah request terlampaui, coba lagi nanti" {
+		t.Fatalf("localized 429 = %d %q", res.Code, res.Message)
+	}
+}
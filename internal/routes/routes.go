@@ -1,29 +1,99 @@
 package routes
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
 	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
 	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
 	"github.com/oktaharis/uji-teknis-godigi/internal/handlers"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+	"github.com/oktaharis/uji-teknis-godigi/internal/jobs"
+	"github.com/oktaharis/uji-teknis-godigi/internal/metrics"
+	"github.com/oktaharis/uji-teknis-godigi/internal/middleware"
 	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/openapi"
+	"github.com/oktaharis/uji-teknis-godigi/internal/ratelimit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
 	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+	"github.com/oktaharis/uji-teknis-godigi/internal/tracing"
 )
 
-func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
+// SetupRouter membangun router API. m nil berarti metrik Prometheus dimatikan.
+func SetupRouter(cfg *config.Config, db *gorm.DB, m *metrics.Metrics) *gin.Engine {
     r := gin.New()
-    r.Use(gin.Logger())
-    // r.Use(middleware.RecoveryJSON(), middleware.NotFoundJSON()) // kalau kamu pakai
+    r.HandleMethodNotAllowed = true
+    // IP client (log, audit, rate limit) dari X-Forwarded-For hanya kalau koneksi datang dari proxy ini
+    if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+        slog.Error("invalid trusted proxies", "error", err)
+    }
+    r.Use(middleware.RequestID(), tracing.Middleware(), middleware.Logger(slog.Default()))
+    if m != nil {
+        r.Use(m.Middleware()) // di luar recovery supaya panic tetap tercatat sebagai 500
+    }
+    r.Use(middleware.JSONRecovery())
+    r.Use(middleware.Locale()) // bahasa pesan dari Accept-Language (id/en)
+    r.Use(middleware.SecurityHeaders(cfg.Security), middleware.CORS(cfg.CORS), middleware.BodyLimit(cfg.MaxBodyBytes))
+    r.NoRoute(middleware.NotFoundHandler())
+    r.NoMethod(middleware.MethodNotAllowedHandler())
 
     // Public (tanpa auth)
-    ah  := handlers.NewAuthHandler(cfg, db)
+    store := repository.NewGormStore(db)
+    users := service.NewUserService(store)
+    trash := service.NewTrashService(store, jobs.TrashRetention(cfg))
+
+    ah  := handlers.NewAuthHandler(service.NewAuthService(cfg, store))
+    ah.ShowResetToken = cfg.ExposeResetToken()
     uh  := handlers.NewUserHandler()
-    lh  := handlers.NewLeadHandler(db)
-    ph  := handlers.NewProjectHandler(db)
-    uah := handlers.NewUserAdminHandler(db)
+    lh  := handlers.NewLeadHandler(service.NewLeadService(store))
+    ph  := handlers.NewProjectHandler(service.NewProjectService(store))
+    uah := handlers.NewUserAdminHandler(users, trash)
+    uah.ShowResetToken = cfg.ExposeResetToken()
+    adh := handlers.NewAuditHandler(service.NewAuditService(store))
+
+    if db != nil {
+        if err := tracing.InstrumentDB(db); err != nil {
+            slog.Error("tracing: instrument db failed", "error", err)
+        }
+    }
+
+    // Metrik Prometheus; di port terpisah kalau METRICS_ADDR diisi (lihat cmd/api)
+    if m != nil {
+        if db != nil {
+            if err := m.InstrumentDB(db, "main"); err != nil {
+                slog.Error("metrics: instrument db failed", "error", err)
+            }
+            if err := m.RegisterBusiness(store); err != nil {
+                slog.Error("metrics: register business gauges failed", "error", err)
+            }
+        }
+        if cfg.MetricsAddr == "" {
+            r.GET("/metrics", metrics.RequireToken(cfg.MetricsToken), gin.WrapH(m.Handler()))
+        }
+    }
+
+    // Probe orchestrator dan info build, tanpa auth
+    hh := handlers.NewHealthHandler(health.Database(db), health.Migrations(db, database.Dialect(cfg.DBDSN)))
+    r.GET("/healthz", hh.Live)
+    r.GET("/readyz", hh.Ready)
+    r.GET("/version", hh.Version)
+
+    // Dokumentasi API
+    if cfg.Features.APIDocs {
+        r.GET("/openapi.json", openapi.JSONHandler(openapi.Build(apiInfo, apiOperations)))
+        r.GET("/docs", openapi.UIHandler(apiInfo.Title, "/openapi.json"))
+    }
+
+    // Rate limit token bucket per user (per IP sebelum login), lihat RATE_LIMIT_*
+    limit := ratelimit.New(ratelimit.NewMemoryStore(), cfg.RateLimit)
 
     pub := r.Group("/auth")
+    pub.Use(audit.Middleware(), limit.Group(ratelimit.GroupAuth))
     {
         pub.POST("/register", ah.Register)
         pub.POST("/login", ah.Login)
@@ -33,7 +103,7 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 
     // Protected (WAJIB AuthRequired agar `user` ada di context)
     api := r.Group("/")
-    api.Use(auth.AuthRequired(cfg, db))
+    api.Use(auth.AuthRequired(cfg, db), audit.Middleware(), limit.Methods()) // limit setelah auth supaya kuncinya ID user
     {
         api.POST("/auth/logout", ah.Logout)
         api.GET("/me", uh.Me)
@@ -41,17 +111,23 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
         // Leads
         api.POST("/leads", lh.Create)
         api.GET("/leads", lh.List)
-        api.GET("/leads/summary", lh.Summary)
+        api.GET("/leads/summary", limit.Group(ratelimit.GroupExport), lh.Summary) // agregat berat: batas export di atas batas read
+        api.GET("/leads/trash", lh.Trash)
         api.GET("/leads/:id", lh.Get)
         api.PUT("/leads/:id", lh.Update)
+        api.PATCH("/leads/:id", lh.Patch)
         api.DELETE("/leads/:id", lh.Delete)
+        api.POST("/leads/:id/restore", lh.Restore)
 
         // Projects
         api.POST("/projects", ph.Create)
         api.GET("/projects", ph.List)
+        api.GET("/projects/trash", ph.Trash)
         api.GET("/projects/:id", ph.Get)
         api.PUT("/projects/:id", ph.Update)
+        api.PATCH("/projects/:id", ph.Patch)
         api.DELETE("/projects/:id", ph.Delete)
+        api.POST("/projects/:id/restore", ph.Restore)
 
         // ADMIN — HARUS di dalam `api` supaya AuthRequired jalan lebih dulu
         admin := api.Group("/admin")
@@ -59,18 +135,35 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
         {
             admin.POST("/users", uah.Create)
             admin.GET("/users", uah.List)
+            admin.GET("/users/trash", uah.Trash)
             admin.GET("/users/:id", uah.Get)
             admin.PUT("/users/:id", uah.Update)
+            admin.PATCH("/users/:id", uah.Patch)
             admin.DELETE("/users/:id", uah.Delete)
+
+            // Lifecycle user
+            admin.POST("/users/:id/suspend", uah.Suspend)
+            admin.POST("/users/:id/deactivate", uah.Deactivate)
+            admin.POST("/users/:id/reactivate", uah.Reactivate)
+            admin.POST("/users/:id/force-logout", uah.ForceLogout)
+            admin.POST("/users/:id/reset-password", uah.ResetPassword)
+            admin.POST("/users/:id/reassign", uah.Reassign)
+            admin.POST("/users/:id/restore", uah.Restore)
+
+            // Trash
+            admin.POST("/trash/purge", uah.PurgeTrash)
+
+            // Audit trail
+            admin.GET("/audit", adh.List)
         }
 
         // (opsional) endpoint debug
-        api.GET("/debug/whoami", func(c *gin.Context) {
-            u := c.MustGet("user").(models.User)
-            response.OK(c, gin.H{"id": u.ID, "email": u.Email, "role": u.Role}, "whoami")
-        })
+        if cfg.Features.DebugRoutes {
+            api.GET("/debug/whoami", func(c *gin.Context) {
+                u := c.MustGet("user").(models.User)
+                response.OK(c, gin.H{"id": u.ID, "email": u.Email, "role": u.Role}, "whoami")
+            })
+        }
     }
-
-    // r.NoRoute(func(c *gin.Context){ response.NotFound(c, "route not found") })
     return r
 }
@@ -0,0 +1,166 @@
+package routes_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/response"
+	"github.com/oktaharis/uji-teknis-godigi/internal/routes"
+)
+
+// wantHeaders cek nilai header; "" berarti header tidak boleh ada.
+func wantHeaders(kv ...string) func(t *testing.T, r *apitest.Response) {
+	return func(t *testing.T, r *apitest.Response) {
+		t.Helper()
+		for i := 0; i+1 < len(kv); i += 2 {
+			if got := r.Header.Get(kv[i]); got != kv[i+1] {
+				t.Errorf("%s = %q, want %q", kv[i], got, kv[i+1])
+			}
+		}
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	h := apitest.New(t)
+	h.Run([]apitest.Case{
+		{Name: "api response", Method: http.MethodGet, Path: "/leads", As: apitest.AsUser, Want: http.StatusOK,
+			Check: wantHeaders(
+				"X-Content-Type-Options", "nosniff",
+				"X-Frame-Options", "DENY",
+				"Referrer-Policy", "no-referrer",
+				"Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'",
+				"Strict-Transport-Security", "")},
+		{Name: "hsts behind tls proxy", Method: http.MethodGet, Path: "/healthz", Header: []string{"X-Forwarded-Proto", "https"},
+			Want: http.StatusOK, Check: wantHeaders("Strict-Transport-Security", "max-age=31536000")},
+		{Name: "error response", Method: http.MethodGet, Path: "/nope", Want: http.StatusNotFound,
+			Check: wantHeaders("X-Content-Type-Options", "nosniff")},
+	})
+
+	res := h.Do(http.MethodGet, "/docs", "", nil)
+	csp := res.Header.Get("Content-Security-Policy")
+	m := regexp.MustCompile(`script-src 'nonce-([^']+)'`).FindStringSubmatch(csp)
+	if m == nil || !strings.Contains(csp, "connect-src 'self'") {
+		t.Fatalf("docs CSP = %q", csp)
+	}
+	if !strings.Contains(string(res.Raw), `<script nonce="`) {
+		t.Fatalf("docs inline script has no nonce: %s", res.Raw)
+	}
+	if again := h.Do(http.MethodGet, "/docs", "", nil).Header.Get("Content-Security-Policy"); again == csp {
+		t.Fatal("docs nonce must change per request")
+	}
+}
+
+func TestCORS(t *testing.T) {
+	h := apitest.New(t)
+	cfg := *h.Cfg
+	cfg.CORS = config.CORS{AllowedOrigins: []string{"https://app.godigi.id"}, AllowCredentials: true, MaxAge: 10 * time.Minute}
+	h.Router = routes.SetupRouter(&cfg, h.DB, nil)
+
+	preflight := func(origin string) []string {
+		return []string{"Origin", origin, "Access-Control-Request-Method", "PATCH",
+			"Access-Control-Request-Headers", "authorization, content-type, if-match"}
+	}
+	h.Run([]apitest.Case{
+		{Name: "preflight allowed", Method: http.MethodOptions, Path: "/leads/1", Header: preflight("https://APP.godigi.id"),
+			Want: http.StatusNoContent, Check: func(t *testing.T, r *apitest.Response) {
+				wantHeaders(
+					"Access-Control-Allow-Origin", "https://APP.godigi.id",
+					"Access-Control-Allow-Credentials", "true",
+					"Access-Control-Max-Age", "600")(t, r)
+				for _, want := range []string{"PATCH", "DELETE"} {
+					if !strings.Contains(r.Header.Get("Access-Control-Allow-Methods"), want) {
+						t.Errorf("Allow-Methods %q misses %s", r.Header.Get("Access-Control-Allow-Methods"), want)
+					}
+				}
+				if !strings.Contains(r.Header.Get("Access-Control-Allow-Headers"), "If-Match") {
+					t.Errorf("Allow-Headers = %q", r.Header.Get("Access-Control-Allow-Headers"))
+				}
+			}},
+		{Name: "preflight other origin", Method: http.MethodOptions, Path: "/leads", Header: preflight("https://evil.example"),
+			Want: http.StatusForbidden, Code: response.CodeForbidden, Check: wantHeaders("Access-Control-Allow-Origin", "")},
+		{Name: "simple request allowed", Method: http.MethodGet, Path: "/leads", As: apitest.AsUser,
+			Header: []string{"Origin", "https://app.godigi.id"}, Want: http.StatusOK,
+			Check: func(t *testing.T, r *apitest.Response) {
+				wantHeaders("Access-Control-Allow-Origin", "https://app.godigi.id", "Access-Control-Allow-Credentials", "true")(t, r)
+				if !strings.Contains(r.Header.Get("Access-Control-Expose-Headers"), "ETag") {
+					t.Errorf("Expose-Headers = %q", r.Header.Get("Access-Control-Expose-Headers"))
+				}
+				if !strings.Contains(strings.Join(r.Header.Values("Vary"), ","), "Origin") {
+					t.Errorf("Vary = %v, want Origin", r.Header.Values("Vary"))
+				}
+			}},
+		{Name: "simple request other origin", Method: http.MethodGet, Path: "/leads", As: apitest.AsUser,
+			Header: []string{"Origin", "https://evil.example"}, Want: http.StatusOK,
+			Check: wantHeaders("Access-Control-Allow-Origin", "")},
+	})
+
+	// Tanpa origin yang dikonfigurasi, CORS mati.
+	h.Router = routes.SetupRouter(h.Cfg, h.DB, nil)
+	res := h.Do(http.MethodGet, "/healthz", "", nil, "Origin", "https://app.godigi.id")
+	if res.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("CORS headers sent without CORS_ALLOWED_ORIGINS")
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	h := apitest.New(t)
+	clientIP := func() string {
+		t.Helper()
+		h.Logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		req.RemoteAddr = "10.0.0.5:4321"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		h.Router.ServeHTTP(httptest.NewRecorder(), req)
+		lines := h.LogLines()
+		if len(lines) == 0 {
+			t.Fatal("no request log")
+		}
+		return lines[len(lines)-1]["ip"].(string)
+	}
+
+	if ip := clientIP(); ip != "10.0.0.5" {
+		t.Fatalf("without trusted proxies ip = %s, want the peer address", ip)
+	}
+	cfg := *h.Cfg
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	h.Router = routes.SetupRouter(&cfg, h.DB, nil)
+	if ip := clientIP(); ip != "203.0.113.7" {
+		t.Fatalf("behind trusted proxy ip = %s, want X-Forwarded-For", ip)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := apitest.New(t)
+	cfg := *h.Cfg
+	cfg.MaxBodyBytes = 256
+	h.Router = routes.SetupRouter(&cfg, h.DB, nil)
+
+	big := map[string]string{"company_name": strings.Repeat("x", 300), "contact_name": "Siti", "email": "big@example.com"}
+	h.Run([]apitest.Case{
+		{Name: "small body", Method: http.MethodPost, Path: "/leads", As: apitest.AsUser,
+			Body: map[string]string{"company_name": "PT Kecil", "contact_name": "Siti", "email": "kecil@example.com"},
+			Want: http.StatusCreated},
+		{Name: "content-length over limit", Method: http.MethodPost, Path: "/leads", As: apitest.AsUser, Body: big,
+			Want: http.StatusRequestEntityTooLarge, Code: response.CodePayloadTooLarge},
+	})
+
+	// Body chunked (tanpa Content-Length) dipotong saat dibaca handler.
+	req := httptest.NewRequest(http.MethodPatch, "/leads/1", io.MultiReader(strings.NewReader(`{"notes":"`),
+		strings.NewReader(strings.Repeat("x", 300)), strings.NewReader(`"}`)))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+h.UserToken())
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), response.CodePayloadTooLarge) {
+		t.Fatalf("chunked body: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,81 @@
+package routes_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/apitest"
+)
+
+func TestTracing(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	h := apitest.New(t)
+	token := h.UserToken()
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	traceparent := []string{"traceparent", "00-" + traceID + "-" + parentID + "-01"}
+
+	t.Run("error response and logs carry the incoming trace id", func(t *testing.T) {
+		r := h.Do(http.MethodGet, "/leads/9999", token, nil, traceparent...)
+		if r.Error == nil || r.Error.TraceID != traceID {
+			t.Fatalf("error = %+v, want trace_id %s", r.Error, traceID)
+		}
+		var found bool
+		for _, l := range h.LogLines() {
+			if l["msg"] == "request" && l["path"] == "/leads/9999" {
+				found = l["trace_id"] == traceID && l["span_id"] != nil
+			}
+		}
+		if !found {
+			t.Fatal("access log without trace_id")
+		}
+	})
+
+	t.Run("server span with one child span per query", func(t *testing.T) {
+		sr.Reset()
+		h.Do(http.MethodGet, "/leads/summary?from=2000-01-01", token, nil, traceparent...)
+
+		var server sdktrace.ReadOnlySpan
+		for _, s := range sr.Ended() {
+			if s.Name() == "GET /leads/summary" {
+				server = s
+			}
+		}
+		if server == nil {
+			t.Fatalf("no server span, got %d spans", len(sr.Ended()))
+		}
+		if server.Parent().SpanID().String() != parentID || server.SpanContext().TraceID().String() != traceID {
+			t.Fatalf("server span not continued from traceparent: parent %s", server.Parent().SpanID())
+		}
+
+		var queries int
+		for _, s := range sr.Ended() {
+			if !strings.HasPrefix(s.Name(), "gorm.") || s.Parent().SpanID() != server.SpanContext().SpanID() {
+				continue
+			}
+			queries++
+			for _, a := range s.Attributes() {
+				if a.Key == "db.statement" && a.Value.AsString() == "" {
+					t.Errorf("%s without db.statement", s.Name())
+				}
+			}
+		}
+		// auth + count lead + group by (status, source, region) + statistik deal
+		if queries < 5 {
+			t.Fatalf("summary produced %d query spans, want at least 5", queries)
+		}
+	})
+}
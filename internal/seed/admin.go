@@ -0,0 +1,49 @@
+package seed
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+var ErrAdminConfig = errors.New("ADMIN_EMAIL and ADMIN_PASSWORD (min 6 chars) are required")
+
+// EnsureAdmin membuat admin awal. Kalau email sudah terdaftar, user tersebut dijadikan admin aktif
+// (termasuk di-restore dari trash) tanpa mengganti password-nya. created true kalau user baru dibuat.
+func EnsureAdmin(db *gorm.DB, name, email, password string) (u models.User, created bool, err error) {
+	if email == "" || len(password) < 6 {
+		return u, false, ErrAdminConfig
+	}
+	if name == "" {
+		name = "Administrator"
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// user aktif didahulukan; yang di trash hanya dipulihkan kalau email-nya belum dipakai lagi
+		res := tx.Unscoped().Where("email = ?", email).Order("deleted_at IS NOT NULL").Limit(1).Find(&u)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			hash, err := auth.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			u = models.User{Name: name, Email: email, PasswordHash: hash, Role: "admin", Status: models.UserStatusActive}
+			created = true
+			return tx.Create(&u).Error
+		}
+		if err := tx.Unscoped().Model(&u).Updates(map[string]any{
+			"role":       "admin",
+			"status":     models.UserStatusActive,
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.First(&u, u.ID).Error
+	})
+	return u, created, err
+}
@@ -0,0 +1,127 @@
+package seed
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+)
+
+// dataset.sql ditulis dengan sintaks PostgreSQL (CREATE TABLE + INSERT dengan id eksplisit).
+// Skema sudah dibuat migration, jadi yang dipakai hanya statement INSERT-nya.
+//
+//go:embed dataset.sql
+var datasetSQL string
+
+// DatasetResult jumlah statement INSERT yang dijalankan dan baris yang benar-benar masuk.
+type DatasetResult struct {
+	Statements int
+	Inserted   int64
+}
+
+// Dataset memuat dataset bawaan. Lihat LoadDataset.
+func Dataset(db *gorm.DB, dialect string) (DatasetResult, error) {
+	return LoadDataset(db, dialect, strings.NewReader(datasetSQL))
+}
+
+// LoadDataset menjalankan statement INSERT dari r dalam satu transaksi. Setiap INSERT diubah
+// menjadi bentuk "abaikan kalau sudah ada" milik dialect, sehingga aman dijalankan berulang:
+// baris dengan primary key yang sudah ada dilewati, bukan ditimpa.
+func LoadDataset(db *gorm.DB, dialect string, r io.Reader) (DatasetResult, error) {
+	stmts, err := insertStatements(r)
+	if err != nil {
+		return DatasetResult{}, err
+	}
+	var res DatasetResult
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, s := range stmts {
+			stmt, err := ignoreDuplicates(dialect, s)
+			if err != nil {
+				return err
+			}
+			q := tx.Exec(stmt)
+			if q.Error != nil {
+				return fmt.Errorf("%w\n%s", q.Error, s)
+			}
+			res.Statements++
+			res.Inserted += q.RowsAffected
+		}
+		if dialect == database.Postgres {
+			return resetSequences(tx)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// insertStatements mengambil statement INSERT INTO; statement lain (CREATE TABLE dsb.) dilewati.
+func insertStatements(r io.Reader) ([]string, error) {
+	var out []string
+	var cur strings.Builder
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteByte(' ')
+		if !strings.HasSuffix(line, ";") {
+			continue
+		}
+		stmt := strings.TrimSuffix(strings.TrimSpace(cur.String()), ";")
+		cur.Reset()
+		if strings.HasPrefix(strings.ToUpper(stmt), "INSERT INTO ") {
+			out = append(out, stmt)
+		}
+	}
+	return out, sc.Err()
+}
+
+// primaryKeys kolom primary key per tabel, dipakai MySQL untuk ON DUPLICATE KEY UPDATE.
+var primaryKeys = map[string]string{
+	"leads":           "lead_id",
+	"deals":           "deal_id",
+	"users":           "id",
+	"projects":        "id",
+	"password_resets": "id",
+}
+
+var insertTable = regexp.MustCompile(`^(?i:INSERT INTO)\s+["\x60]?([a-z_]+)`)
+
+// ignoreDuplicates mengubah stmt supaya hanya baris yang bentrok di key unik yang dilewati; error lain
+// tetap menggagalkan seed. MySQL memakai ON DUPLICATE KEY UPDATE <pk> = <pk> (tanpa efek), bukan
+// INSERT IGNORE yang juga menelan pelanggaran foreign key, nilai terpotong dan nilai tidak valid.
+// SQLite dan PostgreSQL memakai ON CONFLICT DO NOTHING (INSERT OR IGNORE SQLite juga menelan NOT NULL/CHECK).
+func ignoreDuplicates(dialect, stmt string) (string, error) {
+	switch dialect {
+	case database.MySQL:
+		m := insertTable.FindStringSubmatch(stmt)
+		if m == nil || primaryKeys[m[1]] == "" {
+			return "", fmt.Errorf("seed: unsupported table in statement: %.80s", stmt)
+		}
+		pk := primaryKeys[m[1]]
+		return stmt + " ON DUPLICATE KEY UPDATE " + pk + " = " + pk, nil
+	default:
+		return stmt + " ON CONFLICT DO NOTHING", nil
+	}
+}
+
+// resetSequences menyamakan sequence BIGSERIAL dengan id terbesar. PostgreSQL tidak memajukan
+// sequence saat id diisi eksplisit, tanpa ini insert berikutnya bentrok di primary key.
+func resetSequences(tx *gorm.DB) error {
+	for _, t := range [][2]string{{"leads", "lead_id"}, {"deals", "deal_id"}} {
+		q := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(MAX(%[2]s), 0) + 1, false) FROM %[1]s", t[0], t[1])
+		if err := tx.Exec(q).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+// DemoDomain domain email user demo, dipakai juga untuk menghitung user demo yang sudah ada.
+const DemoDomain = "demo.godigi.id"
+
+// DemoOptions jumlah data sintetis yang dibuat. Seed yang sama menghasilkan data yang sama.
+type DemoOptions struct {
+	Users        int
+	Leads        int
+	Projects     int
+	UserPassword string
+	Seed         int64
+}
+
+type DemoResult struct {
+	Users    int
+	Leads    int
+	Deals    int
+	Projects int
+}
+
+var (
+	companyPrefixes = []string{"PT", "PT", "PT", "CV"}
+	companyWords    = []string{"Nusantara", "Sejahtera", "Cemerlang", "Inovasi", "Maju", "Sentosa", "Mitra", "Bumi", "Garuda", "Samudra", "Cahaya", "Karya", "Mandala", "Surya", "Global"}
+	companySuffixes = []string{"Jaya", "Indo", "Mandiri", "Tech", "Karya", "Abadi", "Digital", "Makmur", "Perkasa", "Utama", "Solusi"}
+	firstNames      = []string{"Andi", "Arif", "Ayu", "Bagus", "Budi", "Citra", "Dewi", "Dimas", "Eka", "Fajar", "Farhan", "Gita", "Hendra", "Indah", "Iqbal", "Joko", "Kartika", "Lina", "Maya", "Nadia", "Putri", "Rina", "Rudi", "Sari", "Siti", "Teguh", "Wahyu", "Yudi"}
+	lastNames       = []string{"Pratama", "Saputra", "Wijaya", "Santoso", "Hidayat", "Nugroho", "Lestari", "Kusuma", "Siregar", "Simanjuntak", "Wibowo", "Permata", "Setiawan", "Rahmawati"}
+	sources         = []string{"Cold Call", "Email Campaign", "Event", "Referral", "Social Media", "Website"}
+	industries      = []string{"Education", "Finance", "Healthcare", "Manufacturing", "Retail", "SaaS", "Logistics", "Hospitality"}
+	regions         = []string{"Jabodetabek", "Jawa Barat", "Jawa Tengah", "Jawa Timur", "Bali", "Sumatera", "Kalimantan", "Sulawesi"}
+	leadStatuses    = []string{"New", "Contacted", "Nurturing", "Qualified", "Won", "Lost"}
+	leadNotes       = []string{"Cold lead", "Follow-up via email", "Interested in annual plan", "Meeting scheduled", "Price sensitive", "Requested demo"}
+	salesReps       = []string{"Ayu", "Budi", "Citra", "Dimas", "Eka", "Farhan"}
+	dealNames       = []string{"Subscription Basic", "Subscription Pro", "Subscription Enterprise"}
+	dealAmounts     = []int64{15000000, 25000000, 35000000, 50000000, 75000000, 100000000, 150000000, 200000000}
+	dealTerms       = []int{1, 3, 6, 12}
+	projectKinds    = []string{"Implementasi ERP", "Migrasi Cloud", "Aplikasi Mobile", "Integrasi Payment Gateway", "Dashboard Penjualan", "Portal Pelanggan", "Otomasi Gudang"}
+	projectStatuses = []string{"planned", "in_progress", "on_hold", "done", "canceled"}
+)
+
+// Demo membuat user, lead (beserta deal) dan project sintetis dengan data perusahaan dan wilayah Indonesia.
+// Email user demo diberi nomor urut lanjutan, jadi aman dijalankan berkali-kali.
+func Demo(db *gorm.DB, opt DemoOptions) (DemoResult, error) {
+	rng := rand.New(rand.NewSource(opt.Seed))
+	pick := func(xs []string) string { return xs[rng.Intn(len(xs))] }
+	ptr := func(s string) *string { return &s }
+	// waktu acak dalam dua tahun terakhir
+	since := func(days int) time.Time {
+		return time.Now().Add(-time.Duration(rng.Int63n(int64(days) * int64(24*time.Hour)))).Truncate(time.Second)
+	}
+
+	var res DemoResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		reps := salesReps
+		if opt.Users > 0 {
+			hash, err := auth.HashPassword(opt.UserPassword)
+			if err != nil {
+				return err
+			}
+			var existing int64
+			if err := tx.Unscoped().Model(&models.User{}).Where("email LIKE ?", "%@"+DemoDomain).Count(&existing).Error; err != nil {
+				return err
+			}
+			users := make([]models.User, 0, opt.Users)
+			reps = nil
+			for i := 0; i < opt.Users; i++ {
+				first, last := pick(firstNames), pick(lastNames)
+				users = append(users, models.User{
+					Name:         first + " " + last,
+					Email:        fmt.Sprintf("%s.%s.%d@%s", strings.ToLower(first), strings.ToLower(last), int(existing)+i+1, DemoDomain),
+					PasswordHash: hash,
+					Role:         "user",
+					Status:       models.UserStatusActive,
+				})
+				reps = append(reps, first+" "+last)
+			}
+			if err := tx.CreateInBatches(&users, 100).Error; err != nil {
+				return err
+			}
+			res.Users = len(users)
+		}
+
+		leads := make([]models.Lead, 0, opt.Leads)
+		for i := 0; i < opt.Leads; i++ {
+			company := fmt.Sprintf("%s %s %s", pick(companyPrefixes), pick(companyWords), pick(companySuffixes))
+			contact := pick(firstNames)
+			slug := strings.ToLower(strings.ReplaceAll(strings.SplitN(company, " ", 2)[1], " ", ""))
+			status := pick(leadStatuses)
+			lead := models.Lead{
+				CreatedAt:   since(730),
+				CompanyName: company,
+				ContactName: contact,
+				Email:       fmt.Sprintf("%s@%s.co.id", strings.ToLower(contact), slug),
+				Phone:       ptr(fmt.Sprintf("+628%d%08d", 11+rng.Intn(9), rng.Intn(100000000))),
+				Source:      ptr(pick(sources)),
+				Industry:    ptr(pick(industries)),
+				Region:      ptr(pick(regions)),
+				SalesRep:    ptr(pick(reps)),
+				Status:      &status,
+				Notes:       ptr(pick(leadNotes)),
+			}
+			if status == "Qualified" || status == "Won" || status == "Lost" {
+				for n := 1 + rng.Intn(2); n > 0; n-- {
+					stage := "Pending"
+					switch status {
+					case "Won":
+						stage = "Won"
+					case "Lost":
+						stage = "Lost"
+					}
+					lead.Deals = append(lead.Deals, models.Deal{
+						DealName:   ptr(pick(dealNames)),
+						AmountIDR:  dealAmounts[rng.Intn(len(dealAmounts))],
+						Currency:   "IDR",
+						TermMonths: dealTerms[rng.Intn(len(dealTerms))],
+						Stage:      stage,
+						ClosedAt:   lead.CreatedAt.Add(time.Duration(1+rng.Intn(90)) * 24 * time.Hour),
+					})
+				}
+				res.Deals += len(lead.Deals)
+			}
+			leads = append(leads, lead)
+		}
+		if len(leads) > 0 {
+			if err := tx.CreateInBatches(&leads, 100).Error; err != nil {
+				return err
+			}
+		}
+		res.Leads = len(leads)
+
+		if opt.Projects > 0 {
+			var owners []uint
+			if err := tx.Model(&models.User{}).Where("status = ?", models.UserStatusActive).Pluck("id", &owners).Error; err != nil {
+				return err
+			}
+			projects := make([]models.Project, 0, opt.Projects)
+			for i := 0; i < opt.Projects; i++ {
+				start := since(365)
+				end := start.AddDate(0, 1+rng.Intn(6), 0)
+				p := models.Project{
+					Name:        fmt.Sprintf("%s %s %s", pick(projectKinds), pick(companyWords), pick(companySuffixes)),
+					Description: ptr("Proyek demo hasil seed"),
+					Status:      pick(projectStatuses),
+					StartDate:   &start,
+					EndDate:     &end,
+				}
+				if len(owners) > 0 {
+					p.OwnerUserID = &owners[rng.Intn(len(owners))]
+				}
+				projects = append(projects, p)
+			}
+			if err := tx.CreateInBatches(&projects, 100).Error; err != nil {
+				return err
+			}
+			res.Projects = len(projects)
+		}
+		return nil
+	})
+	return res, err
+}
@@ -0,0 +1,136 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/database"
+	"github.com/oktaharis/uji-teknis-godigi/internal/migrate"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+)
+
+// migrated database SQLite baru yang sudah menjalankan semua migration.
+func migrated(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DBDSN = "sqlite:" + filepath.Join(t.TempDir(), "seed.db")
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+	m, err := migrate.New(sqlDB, database.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+func count(t *testing.T, db *gorm.DB, model any) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(model).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDatasetTwice(t *testing.T) {
+	db := migrated(t)
+	first, err := Dataset(db, database.SQLite)
+	if err != nil {
+		t.Fatalf("first load: %v", err)
+	}
+	leads, deals := count(t, db, &models.Lead{}), count(t, db, &models.Deal{})
+	if leads == 0 || deals == 0 || first.Inserted != leads+deals {
+		t.Fatalf("first load inserted %d, tables have %d leads and %d deals", first.Inserted, leads, deals)
+	}
+
+	second, err := Dataset(db, database.SQLite)
+	if err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	if second.Statements != first.Statements || second.Inserted != 0 {
+		t.Fatalf("second load = %+v, want %d statements and nothing inserted", second, first.Statements)
+	}
+	if l, d := count(t, db, &models.Lead{}), count(t, db, &models.Deal{}); l != leads || d != deals {
+		t.Fatalf("counts changed to %d leads and %d deals, want %d and %d", l, d, leads, deals)
+	}
+}
+
+// Hanya duplikat yang dilewati; pelanggaran constraint lain menggagalkan seluruh load.
+func TestDatasetRejectsInvalidRows(t *testing.T) {
+	db := migrated(t)
+	sql := "INSERT INTO leads (lead_id, company_name, contact_name, email) VALUES (1, 'PT A', 'Ani', 'ani@a.id');\n" +
+		"INSERT INTO leads (lead_id, company_name, contact_name, email) VALUES (2, NULL, 'Bima', 'bima@b.id');\n"
+	if _, err := LoadDataset(db, database.SQLite, strings.NewReader(sql)); err == nil {
+		t.Fatal("NOT NULL violation was ignored")
+	}
+	if n := count(t, db, &models.Lead{}); n != 0 {
+		t.Fatalf("%d leads left after failed load, want 0", n)
+	}
+}
+
+func TestIgnoreDuplicates(t *testing.T) {
+	for _, tc := range []struct {
+		dialect, stmt, want string
+	}{
+		{database.MySQL, "INSERT INTO leads (lead_id) VALUES (1)", "INSERT INTO leads (lead_id) VALUES (1) ON DUPLICATE KEY UPDATE lead_id = lead_id"},
+		{database.MySQL, "insert into `deals` (deal_id) VALUES (1)", "insert into `deals` (deal_id) VALUES (1) ON DUPLICATE KEY UPDATE deal_id = deal_id"},
+		{database.Postgres, "INSERT INTO leads (lead_id) VALUES (1)", "INSERT INTO leads (lead_id) VALUES (1) ON CONFLICT DO NOTHING"},
+		{database.SQLite, "INSERT INTO deals (deal_id) VALUES (1)", "INSERT INTO deals (deal_id) VALUES (1) ON CONFLICT DO NOTHING"},
+	} {
+		if got, err := ignoreDuplicates(tc.dialect, tc.stmt); err != nil || got != tc.want {
+			t.Errorf("%s %q = %q, %v; want %q", tc.dialect, tc.stmt, got, err, tc.want)
+		}
+	}
+	if _, err := ignoreDuplicates(database.MySQL, "INSERT INTO audit_log (id) VALUES (1)"); err == nil {
+		t.Error("mysql: table without known primary key accepted")
+	}
+}
+
+func TestEnsureAdmin(t *testing.T) {
+	db := migrated(t)
+	u, created, err := EnsureAdmin(db, "", "admin@godigi.id", "rahasia123")
+	if err != nil || !created || u.Role != "admin" || u.Name != "Administrator" {
+		t.Fatalf("first call = %+v, created %v, err %v", u, created, err)
+	}
+
+	again, created, err := EnsureAdmin(db, "Lain", "admin@godigi.id", "password-baru")
+	if err != nil || created || again.ID != u.ID {
+		t.Fatalf("second call = id %d, created %v, err %v; want same admin", again.ID, created, err)
+	}
+	if !auth.CheckPassword(again.PasswordHash, "rahasia123") {
+		t.Fatal("second call changed the password")
+	}
+	if n := count(t, db, &models.User{}); n != 1 {
+		t.Fatalf("%d users, want 1", n)
+	}
+
+	// User yang sudah ada (di-suspend dan di trash) dijadikan admin aktif lagi.
+	if err := db.Model(&models.User{}).Where("id = ?", u.ID).Updates(map[string]any{
+		"role": "user", "status": models.UserStatusSuspended, "deleted_at": time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	restored, created, err := EnsureAdmin(db, "", "admin@godigi.id", "rahasia123")
+	if err != nil || created || restored.ID != u.ID || restored.Role != "admin" || !restored.IsActive() || restored.DeletedAt.Valid {
+		t.Fatalf("restore = %+v, created %v, err %v", restored, created, err)
+	}
+
+	if _, _, err := EnsureAdmin(db, "", "", "rahasia123"); !errors.Is(err, ErrAdminConfig) {
+		t.Fatalf("empty email: err = %v, want ErrAdminConfig", err)
+	}
+}
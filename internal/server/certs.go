@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader menyimpan sertifikat TLS dan memuat ulang dari file kalau file-nya berubah. Sertifikat
+// lama tetap dipakai kalau file baru tidak valid (mis. baru setengah ditulis).
+type CertReloader struct {
+	certFile, keyFile string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertReloader memuat sertifikat pertama kali; error kalau file tidak bisa dibaca.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate untuk tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Reload memuat ulang sertifikat kalau waktu modifikasi cert atau key lebih baru dari yang terakhir dimuat.
+// changed true kalau sertifikat diganti.
+func (r *CertReloader) Reload() (changed bool, err error) {
+	mod, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("tls: %w", err)
+	}
+	r.mu.RLock()
+	same := r.cert != nil && !mod.After(r.modTime)
+	r.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("tls: load key pair: %w", err)
+	}
+	r.mu.Lock()
+	r.cert, r.modTime = &cert, mod
+	r.mu.Unlock()
+	return true, nil
+}
+
+// Watch memanggil Reload setiap interval sampai ctx dibatalkan.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			changed, err := r.Reload()
+			switch {
+			case err != nil:
+				slog.Error("tls certificate reload failed, keeping current certificate", "error", err)
+			case changed:
+				slog.Info("tls certificate reloaded", "cert_file", r.certFile)
+			}
+		}
+	}
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,107 @@
+// Package server http.Server untuk production: timeout dan batas header dari config, TLS opsional dengan
+// reload sertifikat, dan graceful shutdown (draining) saat context dibatalkan.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+)
+
+// CertCheckInterval seberapa sering file sertifikat TLS dicek perubahannya.
+const CertCheckInterval = time.Minute
+
+type Server struct {
+	HTTP *http.Server
+
+	// DrainDelay jeda antara readiness mulai gagal dan listener ditutup, supaya load balancer sempat
+	// berhenti mengirim request baru. ShutdownTimeout batas waktu request yang sedang berjalan untuk selesai.
+	DrainDelay      time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// New membuat server untuk addr dengan timeout dan MaxHeaderBytes dari cfg.
+func New(cfg *config.Config, addr string, h http.Handler) *Server {
+	return &Server{
+		HTTP: &http.Server{
+			Addr:              addr,
+			Handler:           h,
+			ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+			ReadTimeout:       cfg.HTTPReadTimeout,
+			WriteTimeout:      cfg.HTTPWriteTimeout,
+			IdleTimeout:       cfg.HTTPIdleTimeout,
+			MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		},
+		DrainDelay:      cfg.ShutdownDrainDelay,
+		ShutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+// UseTLS melayani HTTPS dengan sertifikat dari file. File dicek setiap CertCheckInterval sampai ctx
+// dibatalkan dan dimuat ulang kalau berubah, jadi rotasi sertifikat tidak butuh restart.
+func (s *Server) UseTLS(ctx context.Context, certFile, keyFile string) error {
+	certs, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	go certs.Watch(ctx, CertCheckInterval)
+	s.HTTP.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: certs.GetCertificate}
+	return nil
+}
+
+// Run listen di s.HTTP.Addr lalu memanggil Serve.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.HTTP.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve melayani ln sampai ctx dibatalkan, lalu shutdown graceful: readiness ditandai draining, tunggu
+// DrainDelay, tutup listener dan tunggu request yang berjalan paling lama ShutdownTimeout. Koneksi yang
+// masih ada setelah itu diputus paksa.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	errc := make(chan error, 1)
+	go func() {
+		var err error
+		if s.HTTP.TLSConfig != nil {
+			err = s.HTTP.ServeTLS(ln, "", "")
+		} else {
+			err = s.HTTP.Serve(ln)
+		}
+		errc <- err
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	health.SetDraining(true)
+	slog.Info("server draining", "addr", ln.Addr().String(), "drain_delay", s.DrainDelay.String(),
+		"shutdown_timeout", s.ShutdownTimeout.String())
+	time.Sleep(s.DrainDelay)
+
+	sctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+	if err := s.HTTP.Shutdown(sctx); err != nil {
+		_ = s.HTTP.Close()
+		return fmt.Errorf("server: shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("server stopped", "addr", ln.Addr().String())
+	return nil
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/health"
+)
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	t.Cleanup(func() { health.SetDraining(false) })
+	started, release := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+	srv := New(&config.Config{ShutdownTimeout: 5 * time.Second}, "", h)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	body := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			body <- "error: " + err.Error()
+			return
+		}
+		defer res.Body.Close()
+		b, _ := io.ReadAll(res.Body)
+		body <- string(b)
+	}()
+	<-started
+	cancel()
+
+	// server berhenti menerima koneksi baru tapi menunggu request yang sedang berjalan
+	deadline := time.Now().Add(2 * time.Second)
+	for !health.Draining() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !health.Draining() {
+		t.Fatal("readiness not marked draining after ctx cancel")
+	}
+	select {
+	case err := <-served:
+		t.Fatalf("Serve returned before in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if got := <-body; got != "done" {
+		t.Fatalf("in-flight response = %q", got)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("Serve = %v", err)
+	}
+}
+
+func TestServeShutdownTimeout(t *testing.T) {
+	t.Cleanup(func() { health.SetDraining(false) })
+	started := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done() // selesai hanya kalau koneksi diputus
+	})
+	srv := New(&config.Config{ShutdownTimeout: 50 * time.Millisecond}, "", h)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+	go func() {
+		if res, err := http.Get("http://" + ln.Addr().String()); err == nil {
+			res.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+	if err := <-served; err == nil {
+		t.Fatal("want error when in-flight requests outlive ShutdownTimeout")
+	}
+}
+
+func TestServerTimeoutsFromConfig(t *testing.T) {
+	cfg := &config.Config{HTTPReadHeaderTimeout: time.Second, HTTPReadTimeout: 2 * time.Second,
+		HTTPWriteTimeout: 3 * time.Second, HTTPIdleTimeout: 4 * time.Second, HTTPMaxHeaderBytes: 8 << 10}
+	s := New(cfg, ":0", http.NotFoundHandler()).HTTP
+	if s.ReadHeaderTimeout != time.Second || s.ReadTimeout != 2*time.Second || s.WriteTimeout != 3*time.Second ||
+		s.IdleTimeout != 4*time.Second || s.MaxHeaderBytes != 8<<10 {
+		t.Fatalf("server = %+v", s)
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, "first", time.Now().Add(-time.Hour))
+
+	r, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, r); got != "first" {
+		t.Fatalf("cert = %s", got)
+	}
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Fatalf("Reload unchanged files = %v, %v", changed, err)
+	}
+
+	writeCert(t, certFile, keyFile, "second", time.Now())
+	if changed, err := r.Reload(); !changed || err != nil {
+		t.Fatalf("Reload rotated files = %v, %v", changed, err)
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Fatalf("cert after rotation = %s", got)
+	}
+
+	// file rusak: sertifikat lama tetap dipakai
+	if err := os.WriteFile(certFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	_ = os.Chtimes(certFile, future, future)
+	if _, err := r.Reload(); err == nil {
+		t.Fatal("want error for invalid certificate")
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Fatalf("cert after failed reload = %s", got)
+	}
+
+	if _, err := NewCertReloader(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+		t.Fatal("want error for missing certificate")
+	}
+}
+
+func commonName(t *testing.T, r *CertReloader) string {
+	t.Helper()
+	c, err := r.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(c.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+// writeCert menulis sertifikat self-signed dengan CN cn, waktu modifikasi file diset ke mod.
+func writeCert(t *testing.T, certFile, keyFile, cn string, mod time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: cn},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type AuditService struct {
+	store repository.Store
+}
+
+func NewAuditService(store repository.Store) *AuditService {
+	return &AuditService{store: store}
+}
+
+// List audit log, terbaru duluan kalau tanpa sort.
+func (s *AuditService) List(ctx context.Context, f repository.AuditFilter, p repository.Page) ([]models.AuditLog, repository.PageInfo, error) {
+	return s.store.Audit().List(ctx, f, p)
+}
+
+// record menulis e ke audit_log lewat store, biasanya tx yang sama dengan perubahannya: kalau audit gagal
+// ditulis, perubahannya ikut di-rollback.
+func record(ctx context.Context, store repository.Store, e audit.Entry) error {
+	row := audit.Row(ctx, e)
+	return store.Audit().Create(ctx, &row)
+}
+
+// tokenVersion representasi token_version untuk Before/After audit; field ini tidak ikut JSON user.
+func tokenVersion(v int) map[string]int { return map[string]int{"token_version": v} }
+
+// revokeTokens menaikkan token_version user di tx dan mencatat kenaikannya dengan action tersebut.
+func revokeTokens(ctx context.Context, tx repository.Store, id uint, action string) error {
+	u, err := tx.Users().Get(ctx, id)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	if err := tx.Users().RevokeTokens(ctx, id); err != nil {
+		return err
+	}
+	return record(ctx, tx, audit.Entry{Action: action, EntityType: "user", EntityID: id,
+		Before: tokenVersion(u.TokenVersion), After: tokenVersion(u.TokenVersion + 1)})
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+// Token reset password berlaku 30 menit.
+const passwordResetTTL = 30 * time.Minute
+
+type RegisterInput struct {
+	Name     string `json:"name" binding:"required,min=2"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type LoginInput struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// LoginResult hasil login. User tetap diisi saat login gagal kalau email-nya terdaftar.
+type LoginResult struct {
+	User      models.User
+	Token     string
+	ExpiresIn int64
+	ExpiresAt time.Time
+}
+
+type AuthService struct {
+	cfg   *config.Config
+	store repository.Store
+}
+
+func NewAuthService(cfg *config.Config, store repository.Store) *AuthService {
+	return &AuthService{cfg: cfg, store: store}
+}
+
+func (s *AuthService) Register(ctx context.Context, in RegisterInput) (models.User, error) {
+	if err := check(in); err != nil {
+		return models.User{}, err
+	}
+	hash, err := auth.HashPassword(in.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+	u := models.User{Name: in.Name, Email: in.Email, PasswordHash: hash, Role: "user", Status: models.UserStatusActive}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		err := tx.Users().Create(ctx, &u)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionRegister, EntityType: "user", EntityID: u.ID, After: u, Actor: &u})
+	})
+	return u, err
+}
+
+// Login: ErrInvalidCredentials untuk email/password salah, ErrAccountInactive kalau akun
+// suspended/deactivated (password sudah benar). Login berhasil dan gagal karena kredensial dicatat di
+// audit; kalau audit gagal ditulis, error-nya yang dikembalikan.
+func (s *AuthService) Login(ctx context.Context, in LoginInput) (LoginResult, error) {
+	if err := check(in); err != nil {
+		return LoginResult{}, err
+	}
+	u, err := s.store.Users().GetByEmail(ctx, in.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return LoginResult{}, s.loginFailed(ctx, nil, in.Email)
+	}
+	if err != nil {
+		return LoginResult{}, err
+	}
+	res := LoginResult{User: u}
+	if !auth.CheckPassword(u.PasswordHash, in.Password) {
+		return res, s.loginFailed(ctx, &u, in.Email)
+	}
+	if !u.IsActive() {
+		return res, ErrAccountInactive
+	}
+	res.Token, res.ExpiresAt, err = auth.SignJWT(s.cfg.JWTSecret, u.ID, u.TokenVersion, s.cfg.JWTExpires)
+	if err != nil {
+		return res, err
+	}
+	res.ExpiresIn = s.cfg.JWTExpires
+	return res, record(ctx, s.store, audit.Entry{Action: audit.ActionLogin, EntityType: "user", EntityID: u.ID, Actor: &u})
+}
+
+// loginFailed mencatat login gagal dan mengembalikan ErrInvalidCredentials. u nil kalau email tidak terdaftar.
+func (s *AuthService) loginFailed(ctx context.Context, u *models.User, email string) error {
+	e := audit.Entry{Action: audit.ActionLoginFailed, EntityType: "user"}
+	if u != nil {
+		e.EntityID, e.Actor = u.ID, u
+	} else {
+		e.Extra = map[string]interface{}{"email": email}
+	}
+	if err := record(ctx, s.store, e); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// Logout me-revoke semua token user (token_version naik).
+func (s *AuthService) Logout(ctx context.Context, u models.User) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		return revokeTokens(ctx, tx, u.ID, audit.ActionLogout)
+	})
+}
+
+// ForgotPassword membuat token reset untuk email tersebut. Belum ada mailer, token dikembalikan ke caller.
+func (s *AuthService) ForgotPassword(ctx context.Context, in ForgotPasswordInput) (string, error) {
+	if err := check(in); err != nil {
+		return "", err
+	}
+	u, err := s.store.Users().GetByEmail(ctx, in.Email)
+	if err != nil {
+		return "", notFound(err, ErrUserNotFound)
+	}
+	var token string
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		if token, err = issuePasswordReset(ctx, tx, u.ID); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionForgotPassword, EntityType: "user", EntityID: u.ID, Actor: &u})
+	})
+	return token, err
+}
+
+// ResetPassword mengganti password memakai token reset; semua sesi user ikut di-revoke.
+func (s *AuthService) ResetPassword(ctx context.Context, in ResetPasswordInput) error {
+	if err := check(in); err != nil {
+		return err
+	}
+	pr, err := s.store.PasswordResets().GetByToken(ctx, in.Token)
+	if err != nil || pr.UsedAt != nil || time.Now().After(pr.ExpiresAt) {
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return ErrResetTokenInvalid
+	}
+	hash, err := auth.HashPassword(in.NewPassword)
+	if err != nil {
+		return err
+	}
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().UpdatePassword(ctx, pr.UserID, hash); err != nil {
+			return err
+		}
+		if err := tx.PasswordResets().MarkUsed(ctx, pr.ID, time.Now()); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionResetPassword, EntityType: "user", EntityID: pr.UserID,
+			Actor: &models.User{ID: pr.UserID}})
+	})
+}
+
+// issuePasswordReset membuat token reset baru untuk user.
+func issuePasswordReset(ctx context.Context, store repository.Store, userID uint) (string, error) {
+	pr := models.PasswordReset{
+		UserID:    userID,
+		Token:     uuid.NewString(),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := store.PasswordResets().Create(ctx, &pr); err != nil {
+		return "", err
+	}
+	return pr.Token, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type LeadInput struct {
+	CompanyName string  `json:"company_name" binding:"required"`
+	ContactName string  `json:"contact_name" binding:"required"`
+	Email       string  `json:"email" binding:"required,email"`
+	Phone       *string `json:"phone"`
+	Source      *string `json:"source"`
+	Industry    *string `json:"industry"`
+	Region      *string `json:"region"`
+	SalesRep    *string `json:"sales_rep"`
+	Status      *string `json:"status"`
+	Notes       *string `json:"notes"`
+}
+
+// LeadInputFrom representasi lead yang bisa diedit, dipakai sebagai dokumen dasar merge patch.
+func LeadInputFrom(l models.Lead) LeadInput {
+	return LeadInput{
+		CompanyName: l.CompanyName,
+		ContactName: l.ContactName,
+		Email:       l.Email,
+		Phone:       l.Phone,
+		Source:      l.Source,
+		Industry:    l.Industry,
+		Region:      l.Region,
+		SalesRep:    l.SalesRep,
+		Status:      l.Status,
+		Notes:       l.Notes,
+	}
+}
+
+// apply menimpa semua field lead, field nullable yang nil berarti dikosongkan.
+func (in LeadInput) apply(l *models.Lead) {
+	l.CompanyName = in.CompanyName
+	l.ContactName = in.ContactName
+	l.Email = in.Email
+	l.Phone = in.Phone
+	l.Source = in.Source
+	l.Industry = in.Industry
+	l.Region = in.Region
+	l.SalesRep = in.SalesRep
+	l.Status = in.Status
+	l.Notes = in.Notes
+}
+
+// LeadSummary agregat lead (berdasarkan created_at) dan deal (berdasarkan closed_at).
+type LeadSummary struct {
+	TotalLeads int64            `json:"total_leads"`
+	ByStatus   map[string]int64 `json:"by_status"`
+	BySource   map[string]int64 `json:"by_source"`
+	ByRegion   map[string]int64 `json:"by_region"`
+	Deals      DealSummary      `json:"deals"`
+}
+
+type DealSummary struct {
+	Count          int64            `json:"count"`
+	TotalAmountIDR int64            `json:"total_amount_idr"`
+	AvgTermMonths  float64          `json:"avg_term_months"`
+	ByStage        map[string]int64 `json:"by_stage"`
+}
+
+type LeadService struct {
+	store repository.Store
+}
+
+func NewLeadService(store repository.Store) *LeadService { return &LeadService{store: store} }
+
+func (s *LeadService) Create(ctx context.Context, in LeadInput) (models.Lead, error) {
+	if err := check(in); err != nil {
+		return models.Lead{}, err
+	}
+	var l models.Lead
+	in.apply(&l)
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Leads().Create(ctx, &l); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionCreate, EntityType: "lead", EntityID: l.LeadID, After: l})
+	})
+	return l, err
+}
+
+func (s *LeadService) List(ctx context.Context, f repository.LeadFilter, p repository.Page) ([]models.Lead, repository.PageInfo, error) {
+	return s.store.Leads().List(ctx, f, p)
+}
+
+func (s *LeadService) Get(ctx context.Context, id uint) (models.Lead, error) {
+	l, err := s.store.Leads().Get(ctx, id)
+	return l, notFound(err, ErrLeadNotFound)
+}
+
+// Update menimpa lead dengan input. ErrVersionConflict kalau lead sudah diubah sejak `current` dibaca.
+func (s *LeadService) Update(ctx context.Context, current models.Lead, in LeadInput) (models.Lead, error) {
+	if err := check(in); err != nil {
+		return current, err
+	}
+	l := current
+	in.apply(&l)
+	l.Version = current.Version + 1
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		ok, err := tx.Leads().UpdateIfVersion(ctx, &l, current.Version)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVersionConflict
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionUpdate, EntityType: "lead", EntityID: l.LeadID,
+			Before: current, After: l})
+	})
+	if err != nil {
+		return current, err
+	}
+	return l, nil
+}
+
+// Delete memindahkan lead ke trash. Deals milik lead ikut masuk trash dengan deleted_at yang sama,
+// supaya bisa di-restore bersama. Return lead sebelum dihapus.
+func (s *LeadService) Delete(ctx context.Context, id uint) (models.Lead, error) {
+	l, err := s.Get(ctx, id)
+	if err != nil {
+		return l, err
+	}
+	now := time.Now().Truncate(time.Second)
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		ok, err := tx.Leads().SoftDelete(ctx, l.LeadID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrLeadNotFound
+		}
+		if err := tx.Deals().SoftDeleteByLead(ctx, l.LeadID, now); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionDelete, EntityType: "lead", EntityID: l.LeadID, Before: l})
+	})
+	return l, err
+}
+
+func (s *LeadService) Trash(ctx context.Context, p repository.Page) ([]models.Lead, int64, error) {
+	return s.store.Leads().ListTrashed(ctx, p)
+}
+
+// Restore mengembalikan lead dari trash beserta deals yang terhapus bersamanya.
+func (s *LeadService) Restore(ctx context.Context, id uint) (models.Lead, error) {
+	l, err := s.store.Leads().GetTrashed(ctx, id)
+	if err != nil {
+		return l, notFound(err, ErrLeadNotInTrash)
+	}
+	restored := l
+	restored.DeletedAt = gorm.DeletedAt{}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Deals().RestoreByLead(ctx, l.LeadID, l.DeletedAt.Time); err != nil {
+			return err
+		}
+		if err := tx.Leads().Restore(ctx, l.LeadID); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionRestore, EntityType: "lead", EntityID: l.LeadID,
+			Before: l, After: restored})
+	})
+	if err != nil {
+		return l, err
+	}
+	return restored, nil
+}
+
+func (s *LeadService) Summary(ctx context.Context, r repository.DateRange) (LeadSummary, error) {
+	leads := s.store.Leads()
+	var out LeadSummary
+	var err error
+	if out.TotalLeads, err = leads.Count(ctx, r); err != nil {
+		return out, err
+	}
+	if out.ByStatus, err = leads.CountBy(ctx, "status", r); err != nil {
+		return out, err
+	}
+	if out.BySource, err = leads.CountBy(ctx, "source", r); err != nil {
+		return out, err
+	}
+	if out.ByRegion, err = leads.CountBy(ctx, "region", r); err != nil {
+		return out, err
+	}
+	st, err := s.store.Deals().Stats(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	out.Deals = DealSummary{Count: st.Count, TotalAmountIDR: st.TotalAmountIDR, AvgTermMonths: st.AvgTermMonths, ByStage: st.ByStage}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type ProjectInput struct {
+	Name        string  `json:"name" binding:"required,min=2"`
+	Description *string `json:"description"`
+	Status      *string `json:"status" binding:"omitempty,oneof=planned in_progress on_hold done canceled"`
+	StartDate   *string `json:"start_date"` // "YYYY-MM-DD"
+	EndDate     *string `json:"end_date"`
+	OwnerUserID *uint   `json:"owner_user_id"`
+}
+
+// ProjectInputFrom representasi project yang bisa diedit, dipakai sebagai dokumen dasar merge patch.
+func ProjectInputFrom(p models.Project) ProjectInput {
+	return ProjectInput{
+		Name:        p.Name,
+		Description: p.Description,
+		Status:      &p.Status,
+		StartDate:   formatDatePtr(p.StartDate),
+		EndDate:     formatDatePtr(p.EndDate),
+		OwnerUserID: p.OwnerUserID,
+	}
+}
+
+func formatDatePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
+
+func parseDatePtr(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+type ProjectService struct {
+	store repository.Store
+}
+
+func NewProjectService(store repository.Store) *ProjectService { return &ProjectService{store: store} }
+
+func (s *ProjectService) Create(ctx context.Context, in ProjectInput) (models.Project, error) {
+	if err := check(in); err != nil {
+		return models.Project{}, err
+	}
+	status := "planned"
+	if in.Status != nil {
+		status = *in.Status
+	}
+	p := models.Project{
+		Name:        in.Name,
+		Description: in.Description,
+		Status:      status,
+		StartDate:   parseDatePtr(in.StartDate),
+		EndDate:     parseDatePtr(in.EndDate),
+		OwnerUserID: in.OwnerUserID,
+	}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Projects().Create(ctx, &p); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionCreate, EntityType: "project", EntityID: p.ID, After: p})
+	})
+	return p, err
+}
+
+func (s *ProjectService) List(ctx context.Context, f repository.ProjectFilter, p repository.Page) ([]models.Project, repository.PageInfo, error) {
+	return s.store.Projects().List(ctx, f, p)
+}
+
+func (s *ProjectService) Get(ctx context.Context, id uint) (models.Project, error) {
+	p, err := s.store.Projects().Get(ctx, id)
+	return p, notFound(err, ErrProjectNotFound)
+}
+
+// Update hanya mengubah field yang diisi (nil = tidak berubah), semantik PUT lama.
+func (s *ProjectService) Update(ctx context.Context, current models.Project, in ProjectInput) (models.Project, error) {
+	if err := check(in); err != nil {
+		return current, err
+	}
+	p := current
+	if in.Name != "" {
+		p.Name = in.Name
+	}
+	if in.Description != nil {
+		p.Description = in.Description
+	}
+	if in.Status != nil {
+		p.Status = *in.Status
+	}
+	if in.StartDate != nil {
+		p.StartDate = parseDatePtr(in.StartDate)
+	}
+	if in.EndDate != nil {
+		p.EndDate = parseDatePtr(in.EndDate)
+	}
+	if in.OwnerUserID != nil {
+		p.OwnerUserID = in.OwnerUserID
+	}
+	return s.save(ctx, current, p)
+}
+
+// Replace menimpa semua field; nil berarti dikosongkan (status kosong kembali ke "planned").
+func (s *ProjectService) Replace(ctx context.Context, current models.Project, in ProjectInput) (models.Project, error) {
+	if err := check(in); err != nil {
+		return current, err
+	}
+	p := current
+	p.Name = in.Name
+	p.Description = in.Description
+	p.Status = "planned"
+	if in.Status != nil {
+		p.Status = *in.Status
+	}
+	p.StartDate = parseDatePtr(in.StartDate)
+	p.EndDate = parseDatePtr(in.EndDate)
+	p.OwnerUserID = in.OwnerUserID
+	return s.save(ctx, current, p)
+}
+
+func (s *ProjectService) save(ctx context.Context, current, p models.Project) (models.Project, error) {
+	p.Version = current.Version + 1
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		ok, err := tx.Projects().UpdateIfVersion(ctx, &p, current.Version)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVersionConflict
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionUpdate, EntityType: "project", EntityID: p.ID,
+			Before: current, After: p})
+	})
+	if err != nil {
+		return current, err
+	}
+	return p, nil
+}
+
+// Delete memindahkan project ke trash. Return project sebelum dihapus.
+func (s *ProjectService) Delete(ctx context.Context, id uint) (models.Project, error) {
+	p, err := s.Get(ctx, id)
+	if err != nil {
+		return p, err
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		ok, err := tx.Projects().SoftDelete(ctx, p.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrProjectNotFound
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionDelete, EntityType: "project", EntityID: p.ID, Before: p})
+	})
+	return p, err
+}
+
+func (s *ProjectService) Trash(ctx context.Context, p repository.Page) ([]models.Project, int64, error) {
+	return s.store.Projects().ListTrashed(ctx, p)
+}
+
+func (s *ProjectService) Restore(ctx context.Context, id uint) (models.Project, error) {
+	p, err := s.store.Projects().GetTrashed(ctx, id)
+	if err != nil {
+		return p, notFound(err, ErrProjectNotInTrash)
+	}
+	restored := p
+	restored.DeletedAt = gorm.DeletedAt{}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Projects().Restore(ctx, p.ID); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionRestore, EntityType: "project", EntityID: p.ID,
+			Before: p, After: restored})
+	})
+	if err != nil {
+		return p, err
+	}
+	return restored, nil
+}
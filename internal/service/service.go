@@ -0,0 +1,71 @@
+// Package service berisi aturan bisnis leads, projects, users dan auth. Handler HTTP hanya
+// memetakan request/response ke service; akses data lewat repository.Store.
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+var (
+	ErrLeadNotFound       = errors.New("lead not found")
+	ErrLeadNotInTrash     = errors.New("lead not found in trash")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrProjectNotInTrash  = errors.New("project not found in trash")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserNotInTrash     = errors.New("user not found in trash")
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrVersionConflict    = errors.New("resource has been modified")
+	ErrLastAdmin          = errors.New("cannot remove the last active admin")
+	ErrReassignTarget     = errors.New("reassign target must be an active user")
+	ErrSalesRepAmbiguous  = errors.New("user name is shared by several users")
+	ErrInvalidCredentials = errors.New("email or password is incorrect")
+	ErrAccountInactive    = errors.New("account is not active")
+	ErrResetTokenInvalid  = errors.New("reset token invalid or expired")
+)
+
+// ValidationError input tidak lolos validasi; Err berisi validator.ValidationErrors.
+type ValidationError struct{ Err error }
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Tag "binding" sama dengan yang dipakai gin, jadi aturan validasi tetap ditulis di struct input.
+// Nama field di error memakai tag json supaya sama dengan yang dikirim client.
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(jsonName)
+	return v
+}()
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+func check(in interface{}) error {
+	if err := validate.Struct(in); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+// notFound mengganti repository.ErrNotFound dengan error spesifik entity.
+func notFound(err, target error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return target
+	}
+	return err
+}
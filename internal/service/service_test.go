@@ -0,0 +1,712 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository/memory"
+	"github.com/oktaharis/uji-teknis-godigi/internal/service"
+)
+
+var ctx = context.Background()
+
+func ptr[T any](v T) *T { return &v }
+
+// addUser menyimpan user langsung lewat store; password tidak dipakai di test ini.
+func addUser(t *testing.T, store repository.Store, name, email, role, status string) models.User {
+	t.Helper()
+	u := models.User{Name: name, Email: email, PasswordHash: "x", Role: role, Status: status}
+	if err := store.Users().Create(ctx, &u); err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return u
+}
+
+// auditLog semua baris audit untuk entity tersebut, urut dari yang pertama ditulis.
+func auditLog(t *testing.T, store repository.Store, entityType string, id uint) []models.AuditLog {
+	t.Helper()
+	q := repository.Query{
+		Where: []repository.Cond{
+			{Column: "entity_type", Op: repository.OpEq, Values: []any{entityType}},
+			{Column: "entity_id", Op: repository.OpEq, Values: []any{fmt.Sprint(id)}},
+		},
+		Sort: []repository.Sort{{Column: "id"}},
+	}
+	rows, _, err := store.Audit().List(ctx, repository.AuditFilter{Query: q}, repository.Page{Page: 1, PerPage: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+// lastAudit baris audit terakhir untuk entity tersebut; gagal kalau action-nya bukan want.
+func lastAudit(t *testing.T, store repository.Store, entityType string, id uint, want string) models.AuditLog {
+	t.Helper()
+	rows := auditLog(t, store, entityType, id)
+	if len(rows) == 0 || rows[len(rows)-1].Action != want {
+		t.Fatalf("last audit of %s %d = %+v, want action %s", entityType, id, rows, want)
+	}
+	return rows[len(rows)-1]
+}
+
+// diffOf isi "diff" baris audit, nilai hasil decode JSON (angka menjadi float64).
+func diffOf(t *testing.T, row models.AuditLog) map[string]audit.Change {
+	t.Helper()
+	var payload struct {
+		Diff map[string]audit.Change `json:"diff"`
+	}
+	if err := json.Unmarshal(row.Changes, &payload); err != nil {
+		t.Fatalf("audit changes %s: %v", row.Changes, err)
+	}
+	return payload.Diff
+}
+
+func TestLastAdminGuard(t *testing.T) {
+	ops := []struct {
+		name string
+		run  func(s *service.UserService, admin models.User) error
+	}{
+		{"update role", func(s *service.UserService, admin models.User) error {
+			_, err := s.Update(ctx, admin, service.UpdateUserInput{Role: ptr("user")})
+			return err
+		}},
+		{"replace role", func(s *service.UserService, admin models.User) error {
+			_, err := s.Replace(ctx, admin, service.UserDoc{Name: admin.Name, Email: admin.Email, Role: "user"})
+			return err
+		}},
+		{"delete", func(s *service.UserService, admin models.User) error {
+			_, err := s.Delete(ctx, admin.ID)
+			return err
+		}},
+		{"suspend", func(s *service.UserService, admin models.User) error {
+			_, err := s.Suspend(ctx, admin)
+			return err
+		}},
+		{"deactivate", func(s *service.UserService, admin models.User) error {
+			_, err := s.Deactivate(ctx, admin, service.DeactivateInput{})
+			return err
+		}},
+	}
+	others := []struct {
+		name   string
+		status string // status admin kedua; "" berarti tidak ada
+		want   error
+	}{
+		{"only admin", "", service.ErrLastAdmin},
+		{"other admin suspended", models.UserStatusSuspended, service.ErrLastAdmin},
+		{"other admin active", models.UserStatusActive, nil},
+	}
+	for _, op := range ops {
+		for _, other := range others {
+			t.Run(op.name+"/"+other.name, func(t *testing.T) {
+				store := memory.NewStore()
+				admin := addUser(t, store, "Admin", "admin@test.local", "admin", models.UserStatusActive)
+				if other.status != "" {
+					addUser(t, store, "Admin Dua", "admin2@test.local", "admin", other.status)
+				}
+				if err := op.run(service.NewUserService(store), admin); !errors.Is(err, other.want) {
+					t.Fatalf("err = %v, want %v", err, other.want)
+				}
+				got, err := store.Users().Get(ctx, admin.ID)
+				if other.want != nil && (err != nil || got.Role != "admin" || !got.IsActive()) {
+					t.Fatalf("rejected %s still changed the admin: %+v, %v", op.name, got, err)
+				}
+			})
+		}
+	}
+
+	// Guard hanya untuk turun role atau nonaktif: admin terakhir tetap boleh mengubah namanya.
+	store := memory.NewStore()
+	admin := addUser(t, store, "Admin", "admin@test.local", "admin", models.UserStatusActive)
+	if _, err := service.NewUserService(store).Update(ctx, admin, service.UpdateUserInput{Name: ptr("Admin Baru")}); err != nil {
+		t.Fatalf("rename last admin: %v", err)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	leads := service.NewLeadService(store)
+	projects := service.NewProjectService(store)
+
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	lead, err := leads.Create(ctx, service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proj, err := projects.Create(ctx, service.ProjectInput{Name: "CRM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		update func() error // update pertama berhasil, yang kedua memakai versi lama
+	}{
+		{"user", func() error {
+			_, err := users.Update(ctx, u, service.UpdateUserInput{Name: ptr("Budi Santoso")})
+			return err
+		}},
+		{"lead", func() error {
+			_, err := leads.Update(ctx, lead, service.LeadInput{CompanyName: "PT B", ContactName: "Ani", Email: "ani@a.id"})
+			return err
+		}},
+		{"project", func() error {
+			_, err := projects.Update(ctx, proj, service.ProjectInput{Name: "CRM Baru"})
+			return err
+		}},
+	} {
+		if err := tc.update(); err != nil {
+			t.Fatalf("%s: first update: %v", tc.name, err)
+		}
+		if err := tc.update(); !errors.Is(err, service.ErrVersionConflict) {
+			t.Errorf("%s: stale update err = %v, want ErrVersionConflict", tc.name, err)
+		}
+	}
+}
+
+func TestDuplicateEmail(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	wati := addUser(t, store, "Wati", "wati@test.local", "user", models.UserStatusActive)
+	gone := addUser(t, store, "Lama", "lama@test.local", "user", models.UserStatusActive)
+	if _, err := users.Delete(ctx, gone.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		run  func() error
+	}{
+		{"create", func() error {
+			_, err := users.Create(ctx, service.CreateUserInput{Name: "Budi", Email: "budi@test.local", Password: "secret1", Role: "user"})
+			return err
+		}},
+		{"create different case", func() error {
+			_, err := users.Create(ctx, service.CreateUserInput{Name: "Budi", Email: "BUDI@test.local", Password: "secret1", Role: "user"})
+			return err
+		}},
+		{"update", func() error {
+			_, err := users.Update(ctx, wati, service.UpdateUserInput{Email: ptr("budi@test.local")})
+			return err
+		}},
+		{"replace", func() error {
+			_, err := users.Replace(ctx, wati, service.UserDoc{Name: "Wati", Email: "budi@test.local", Role: "user"})
+			return err
+		}},
+	} {
+		if err := tc.run(); !errors.Is(err, service.ErrEmailTaken) {
+			t.Errorf("%s: err = %v, want ErrEmailTaken", tc.name, err)
+		}
+	}
+	if got, _ := store.Users().Get(ctx, wati.ID); got.Email != "wati@test.local" || got.Version != wati.Version {
+		t.Fatalf("rejected update changed user: %+v", got)
+	}
+
+	// Email user di trash boleh dipakai lagi; user lama tidak bisa di-restore selama email-nya dipakai.
+	again, err := users.Create(ctx, service.CreateUserInput{Name: "Lama", Email: "lama@test.local", Password: "secret1", Role: "user"})
+	if err != nil {
+		t.Fatalf("create email of trashed user: %v", err)
+	}
+	if _, err := users.Restore(ctx, gone.ID); !errors.Is(err, service.ErrEmailTaken) {
+		t.Fatalf("restore with email in use: err = %v, want ErrEmailTaken", err)
+	}
+	if _, err := users.Delete(ctx, again.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Restore(ctx, gone.ID); err != nil {
+		t.Fatalf("restore after email freed: %v", err)
+	}
+}
+
+func TestReassign(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	from := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	to := addUser(t, store, "Wati", "wati@test.local", "user", models.UserStatusActive)
+	suspended := addUser(t, store, "Rudi", "rudi@test.local", "user", models.UserStatusSuspended)
+
+	lead := models.Lead{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id", SalesRep: ptr(from.Name)}
+	if err := store.Leads().Create(ctx, &lead); err != nil {
+		t.Fatal(err)
+	}
+	proj := models.Project{Name: "CRM", Status: "planned", OwnerUserID: ptr(from.ID)}
+	if err := store.Projects().Create(ctx, &proj); err != nil {
+		t.Fatal(err)
+	}
+
+	var ve *service.ValidationError
+	if err := users.Reassign(ctx, from, service.ReassignInput{}); !errors.As(err, &ve) {
+		t.Fatalf("reassign without target: err = %v, want ValidationError", err)
+	}
+	for _, tc := range []struct {
+		name string
+		to   uint
+		want error
+	}{
+		{"self", from.ID, service.ErrReassignTarget},
+		{"not found", 9999, service.ErrReassignTarget},
+		{"inactive", suspended.ID, service.ErrReassignTarget},
+		{"active user", to.ID, nil},
+	} {
+		if err := users.Reassign(ctx, from, service.ReassignInput{ToUserID: tc.to}); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	gotLead, _ := store.Leads().Get(ctx, lead.LeadID)
+	gotProj, _ := store.Projects().Get(ctx, proj.ID)
+	if gotLead.SalesRep == nil || *gotLead.SalesRep != to.Name || gotProj.OwnerUserID == nil || *gotProj.OwnerUserID != to.ID {
+		t.Fatalf("after reassign: lead sales_rep %v, project owner %v", gotLead.SalesRep, gotProj.OwnerUserID)
+	}
+
+	// Deactivate dengan target tidak valid di-rollback utuh: user tetap aktif.
+	if _, err := users.Deactivate(ctx, to, service.DeactivateInput{ReassignTo: ptr(suspended.ID)}); !errors.Is(err, service.ErrReassignTarget) {
+		t.Fatalf("deactivate with invalid target: %v", err)
+	}
+	if got, _ := store.Users().Get(ctx, to.ID); !got.IsActive() {
+		t.Fatalf("user deactivated despite failed reassign: %s", got.Status)
+	}
+}
+
+// sales_rep lead berisi nama; kalau nama dipakai dua user, lead siapa yang dipindah tidak bisa ditentukan.
+func TestReassignAmbiguousName(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	from := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	addUser(t, store, "Budi", "budi2@test.local", "user", models.UserStatusActive)
+	to := addUser(t, store, "Wati", "wati@test.local", "user", models.UserStatusActive)
+	namesake := addUser(t, store, "Wati", "wati2@test.local", "user", models.UserStatusActive)
+	other := addUser(t, store, "Rudi", "rudi@test.local", "user", models.UserStatusActive)
+
+	lead := models.Lead{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id", SalesRep: ptr(from.Name)}
+	if err := store.Leads().Create(ctx, &lead); err != nil {
+		t.Fatal(err)
+	}
+	proj := models.Project{Name: "CRM", Status: "planned", OwnerUserID: ptr(from.ID)}
+	if err := store.Projects().Create(ctx, &proj); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		from, to models.User
+	}{
+		{"source name shared", from, other},
+		{"target name shared", other, to},
+		{"both names shared", from, namesake},
+	} {
+		if err := users.Reassign(ctx, tc.from, service.ReassignInput{ToUserID: tc.to.ID}); !errors.Is(err, service.ErrSalesRepAmbiguous) {
+			t.Errorf("%s: err = %v, want ErrSalesRepAmbiguous", tc.name, err)
+		}
+	}
+	if _, err := users.Deactivate(ctx, from, service.DeactivateInput{ReassignTo: ptr(other.ID)}); !errors.Is(err, service.ErrSalesRepAmbiguous) {
+		t.Fatalf("deactivate with ambiguous name: err = %v", err)
+	}
+
+	gotLead, _ := store.Leads().Get(ctx, lead.LeadID)
+	gotProj, _ := store.Projects().Get(ctx, proj.ID)
+	if *gotLead.SalesRep != from.Name || *gotProj.OwnerUserID != from.ID {
+		t.Fatalf("ambiguous reassign changed data: lead sales_rep %v, project owner %v", *gotLead.SalesRep, *gotProj.OwnerUserID)
+	}
+	if got, _ := store.Users().Get(ctx, from.ID); !got.IsActive() {
+		t.Fatalf("user deactivated despite rejected reassign: %s", got.Status)
+	}
+}
+
+func TestRestoreNotInTrash(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	leads := service.NewLeadService(store)
+	projects := service.NewProjectService(store)
+
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	lead, err := leads.Create(ctx, service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deal := models.Deal{LeadID: lead.LeadID, AmountIDR: 1_000_000, Currency: "IDR", TermMonths: 12, Stage: "won", ClosedAt: time.Now()}
+	if err := store.Deals().Create(ctx, &deal); err != nil {
+		t.Fatal(err)
+	}
+	proj, err := projects.Create(ctx, service.ProjectInput{Name: "CRM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := []struct {
+		name    string
+		id      uint
+		del     func(uint) error
+		restore func(uint) error
+		want    error
+	}{
+		{"lead", lead.LeadID,
+			func(id uint) error { _, err := leads.Delete(ctx, id); return err },
+			func(id uint) error { _, err := leads.Restore(ctx, id); return err },
+			service.ErrLeadNotInTrash},
+		{"project", proj.ID,
+			func(id uint) error { _, err := projects.Delete(ctx, id); return err },
+			func(id uint) error { _, err := projects.Restore(ctx, id); return err },
+			service.ErrProjectNotInTrash},
+		{"user", u.ID,
+			func(id uint) error { _, err := users.Delete(ctx, id); return err },
+			func(id uint) error { _, err := users.Restore(ctx, id); return err },
+			service.ErrUserNotInTrash},
+	}
+	for _, k := range kinds {
+		for _, step := range []struct {
+			name string
+			run  func() error
+			want error
+		}{
+			{"restore alive", func() error { return k.restore(k.id) }, k.want},
+			{"restore unknown id", func() error { return k.restore(9999) }, k.want},
+			{"delete", func() error { return k.del(k.id) }, nil},
+			{"restore", func() error { return k.restore(k.id) }, nil},
+			{"restore twice", func() error { return k.restore(k.id) }, k.want},
+		} {
+			if err := step.run(); !errors.Is(err, step.want) {
+				t.Errorf("%s %s: err = %v, want %v", k.name, step.name, err, step.want)
+			}
+		}
+	}
+
+	// Deal yang ikut terhapus bersama lead kembali saat lead di-restore.
+	if st, err := store.Deals().Stats(ctx, repository.DateRange{}); err != nil || st.ByStage["won"] != 1 {
+		t.Fatalf("deals after lead restore = %v, %v; want the won deal back", st.ByStage, err)
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	admin := addUser(t, store, "Admin", "admin@test.local", "admin", models.UserStatusActive)
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	actx := audit.WithMeta(ctx, audit.Meta{Actor: &admin, IP: "10.0.0.1", RequestID: "req-1"})
+
+	current := func() models.User {
+		t.Helper()
+		got, err := store.Users().Get(ctx, u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	for _, step := range []struct {
+		action, status string
+		run            func(models.User) (models.User, error)
+	}{
+		{audit.ActionSuspend, models.UserStatusSuspended, func(u models.User) (models.User, error) { return users.Suspend(actx, u) }},
+		{audit.ActionReactivate, models.UserStatusActive, func(u models.User) (models.User, error) { return users.Reactivate(actx, u) }},
+		{audit.ActionDeactivate, models.UserStatusDeactivated, func(u models.User) (models.User, error) {
+			return users.Deactivate(actx, u, service.DeactivateInput{})
+		}},
+		{audit.ActionReactivate, models.UserStatusActive, func(u models.User) (models.User, error) { return users.Reactivate(actx, u) }},
+	} {
+		before := current()
+		if _, err := step.run(before); err != nil {
+			t.Fatalf("%s: %v", step.action, err)
+		}
+		after := current()
+		if after.Status != step.status || after.TokenVersion != before.TokenVersion+1 || after.Version != before.Version+1 {
+			t.Fatalf("%s: user = %+v, before %+v", step.action, after, before)
+		}
+		d := diffOf(t, lastAudit(t, store, "user", u.ID, step.action))
+		if d["status"] != (audit.Change{From: before.Status, To: step.status}) {
+			t.Errorf("%s: status diff = %+v", step.action, d["status"])
+		}
+	}
+
+	// Force logout dan reset password hanya menaikkan token_version; perubahannya tetap tercatat.
+	before := current()
+	if err := users.ForceLogout(actx, before); err != nil {
+		t.Fatal(err)
+	}
+	row := lastAudit(t, store, "user", u.ID, audit.ActionForceLogout)
+	want := audit.Change{From: float64(before.TokenVersion), To: float64(before.TokenVersion + 1)}
+	if d := diffOf(t, row); d["token_version"] != want || current().TokenVersion != before.TokenVersion+1 {
+		t.Fatalf("force logout diff = %+v, want token_version %+v", d, want)
+	}
+	if row.ActorUserID == nil || *row.ActorUserID != admin.ID || row.IP != "10.0.0.1" || row.RequestID != "req-1" {
+		t.Fatalf("audit row meta = %+v", row)
+	}
+
+	before = current()
+	token, err := users.ResetPassword(actx, before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diffOf(t, lastAudit(t, store, "user", u.ID, audit.ActionResetPassword)); d["token_version"].To != float64(before.TokenVersion+1) {
+		t.Fatalf("reset password diff = %+v", d)
+	}
+	if err := service.NewAuthService(config.Default(), store).ResetPassword(ctx, service.ResetPasswordInput{Token: token, NewPassword: "rahasia1"}); err != nil {
+		t.Fatalf("reset token from admin reset: %v", err)
+	}
+}
+
+func TestTrashAndRestore(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	leads := service.NewLeadService(store)
+	projects := service.NewProjectService(store)
+
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	lead, err := leads.Create(ctx, service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proj, err := projects.Create(ctx, service.ProjectInput{Name: "CRM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := repository.Page{Page: 1, PerPage: 10}
+
+	for _, k := range []struct {
+		entity  string
+		id      uint
+		del     func() error
+		trash   func() (int64, error)
+		restore func() (bool, error) // true kalau hasil restore sudah tanpa deleted_at
+	}{
+		{"lead", lead.LeadID,
+			func() error { _, err := leads.Delete(ctx, lead.LeadID); return err },
+			func() (int64, error) { _, n, err := leads.Trash(ctx, page); return n, err },
+			func() (bool, error) { l, err := leads.Restore(ctx, lead.LeadID); return !l.DeletedAt.Valid, err }},
+		{"project", proj.ID,
+			func() error { _, err := projects.Delete(ctx, proj.ID); return err },
+			func() (int64, error) { _, n, err := projects.Trash(ctx, page); return n, err },
+			func() (bool, error) { p, err := projects.Restore(ctx, proj.ID); return !p.DeletedAt.Valid, err }},
+		{"user", u.ID,
+			func() error { _, err := users.Delete(ctx, u.ID); return err },
+			func() (int64, error) { _, n, err := users.Trash(ctx, page); return n, err },
+			func() (bool, error) { u, err := users.Restore(ctx, u.ID); return !u.DeletedAt.Valid, err }},
+	} {
+		if err := k.del(); err != nil {
+			t.Fatalf("%s delete: %v", k.entity, err)
+		}
+		lastAudit(t, store, k.entity, k.id, audit.ActionDelete)
+		if n, err := k.trash(); err != nil || n != 1 {
+			t.Fatalf("%s trash = %d, %v; want 1", k.entity, n, err)
+		}
+		if alive, err := k.restore(); err != nil || !alive {
+			t.Fatalf("%s restore = alive %v, %v", k.entity, alive, err)
+		}
+		if n, _ := k.trash(); n != 0 {
+			t.Fatalf("%s trash after restore = %d, want 0", k.entity, n)
+		}
+		// Restore mencatat deleted_at yang kembali null.
+		d := diffOf(t, lastAudit(t, store, k.entity, k.id, audit.ActionRestore))
+		if c, ok := d["deleted_at"]; !ok || c.From == nil || c.To != nil || len(d) != 1 {
+			t.Fatalf("%s restore diff = %+v, want only deleted_at -> null", k.entity, d)
+		}
+	}
+
+	// Purge dengan retention 0 menghapus permanen isi trash dan mencatat jumlahnya.
+	if _, err := leads.Delete(ctx, lead.LeadID); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, err := service.NewTrashService(store, 0).Purge(ctx); err != nil || n["leads"] != 1 {
+		t.Fatalf("purge = %v, %v; want 1 lead", n, err)
+	}
+	if _, err := leads.Restore(ctx, lead.LeadID); !errors.Is(err, service.ErrLeadNotInTrash) {
+		t.Fatalf("restore purged lead: err = %v, want ErrLeadNotInTrash", err)
+	}
+	rows, _, err := store.Audit().List(ctx, repository.AuditFilter{Query: repository.Query{
+		Where: []repository.Cond{{Column: "action", Op: repository.OpEq, Values: []any{audit.ActionPurge}}},
+	}}, page)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("purge audit rows = %d, %v; want 1", len(rows), err)
+	}
+}
+
+func TestAuditEntries(t *testing.T) {
+	store := memory.NewStore()
+	leads := service.NewLeadService(store)
+	admin := addUser(t, store, "Admin", "admin@test.local", "admin", models.UserStatusActive)
+	actx := audit.WithMeta(ctx, audit.Meta{Actor: &admin, IP: "10.0.0.1", RequestID: "req-1"})
+
+	lead, err := leads.Create(actx, service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := lastAudit(t, store, "lead", lead.LeadID, audit.ActionCreate)
+	if row.ActorUserID == nil || *row.ActorUserID != admin.ID || row.ActorEmail != admin.Email || row.IP != "10.0.0.1" || row.RequestID != "req-1" {
+		t.Fatalf("create audit meta = %+v", row)
+	}
+	if d := diffOf(t, row); d["company_name"] != (audit.Change{From: nil, To: "PT A"}) {
+		t.Fatalf("create diff = %+v", d)
+	}
+
+	// Update hanya mencatat field yang berubah (updated_at diabaikan).
+	updated, err := leads.Update(actx, lead, service.LeadInput{CompanyName: "PT B", ContactName: "Ani", Email: "ani@a.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := diffOf(t, lastAudit(t, store, "lead", lead.LeadID, audit.ActionUpdate))
+	want := map[string]audit.Change{
+		"company_name": {From: "PT A", To: "PT B"},
+		"version":      {From: float64(lead.Version), To: float64(updated.Version)},
+	}
+	if len(d) != len(want) || d["company_name"] != want["company_name"] || d["version"] != want["version"] {
+		t.Fatalf("update diff = %+v, want %+v", d, want)
+	}
+
+	// Update yang ditolak tidak meninggalkan baris audit.
+	n := len(auditLog(t, store, "lead", lead.LeadID))
+	if _, err := leads.Update(actx, lead, service.LeadInput{CompanyName: "PT C", ContactName: "Ani", Email: "ani@a.id"}); !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("stale update: err = %v", err)
+	}
+	if _, err := leads.Update(actx, updated, service.LeadInput{CompanyName: "PT C"}); err == nil {
+		t.Fatal("invalid update accepted")
+	}
+	if got := len(auditLog(t, store, "lead", lead.LeadID)); got != n {
+		t.Fatalf("rejected updates wrote %d audit rows", got-n)
+	}
+
+	// Auth: actor diisi user yang login/mendaftar, bukan dari Meta (request belum punya user).
+	authSvc := service.NewAuthService(config.Default(), store)
+	pub := audit.WithMeta(ctx, audit.Meta{IP: "10.0.0.2"})
+	u, err := authSvc.Register(pub, service.RegisterInput{Name: "Budi", Email: "budi@test.local", Password: "rahasia1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row := lastAudit(t, store, "user", u.ID, audit.ActionRegister); row.ActorUserID == nil || *row.ActorUserID != u.ID {
+		t.Fatalf("register actor = %v, want %d", row.ActorUserID, u.ID)
+	}
+	if _, err := authSvc.Login(pub, service.LoginInput{Email: u.Email, Password: "salah"}); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Fatalf("wrong password: err = %v", err)
+	}
+	lastAudit(t, store, "user", u.ID, audit.ActionLoginFailed)
+	if _, err := authSvc.Login(pub, service.LoginInput{Email: u.Email, Password: "rahasia1"}); err != nil {
+		t.Fatal(err)
+	}
+	lastAudit(t, store, "user", u.ID, audit.ActionLogin)
+	if err := authSvc.Logout(pub, u); err != nil {
+		t.Fatal(err)
+	}
+	if d := diffOf(t, lastAudit(t, store, "user", u.ID, audit.ActionLogout)); d["token_version"] != (audit.Change{From: 0.0, To: 1.0}) {
+		t.Fatalf("logout diff = %+v", d)
+	}
+
+	if _, err := authSvc.Login(pub, service.LoginInput{Email: "nobody@test.local", Password: "x"}); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Fatalf("unknown email: err = %v", err)
+	}
+	rows, _, err := store.Audit().List(ctx, repository.AuditFilter{Query: repository.Query{
+		Where: []repository.Cond{{Column: "action", Op: repository.OpEq, Values: []any{audit.ActionLoginFailed}}},
+	}}, repository.Page{Page: 1, PerPage: 10})
+	if err != nil || len(rows) != 2 || rows[0].EntityID != "" || !strings.Contains(string(rows[0].Changes), "nobody@test.local") {
+		t.Fatalf("login_failed rows = %+v, %v; want the unknown email first", rows, err)
+	}
+}
+
+// Update dengan versi basi (If-Match usang di handler) tidak mengubah data dan tidak menulis audit.
+func TestStaleReplace(t *testing.T) {
+	store := memory.NewStore()
+	users := service.NewUserService(store)
+	projects := service.NewProjectService(store)
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	proj, err := projects.Create(ctx, service.ProjectInput{Name: "CRM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		entity  string
+		id      uint
+		replace func(name string) error // selalu memakai versi awal
+		version func() uint
+		name    func() string
+	}{
+		{"user", u.ID,
+			func(name string) error {
+				_, err := users.Replace(ctx, u, service.UserDoc{Name: name, Email: u.Email, Role: u.Role})
+				return err
+			},
+			func() uint { got, _ := store.Users().Get(ctx, u.ID); return got.Version },
+			func() string { got, _ := store.Users().Get(ctx, u.ID); return got.Name }},
+		{"project", proj.ID,
+			func(name string) error {
+				_, err := projects.Replace(ctx, proj, service.ProjectInput{Name: name})
+				return err
+			},
+			func() uint { got, _ := store.Projects().Get(ctx, proj.ID); return got.Version },
+			func() string { got, _ := store.Projects().Get(ctx, proj.ID); return got.Name }},
+	} {
+		start := tc.version()
+		if err := tc.replace("Pertama"); err != nil {
+			t.Fatalf("%s: first replace: %v", tc.entity, err)
+		}
+		n := len(auditLog(t, store, tc.entity, tc.id))
+		if err := tc.replace("Kedua"); !errors.Is(err, service.ErrVersionConflict) {
+			t.Fatalf("%s: stale replace err = %v, want ErrVersionConflict", tc.entity, err)
+		}
+		if tc.version() != start+1 || tc.name() != "Pertama" {
+			t.Errorf("%s: after stale replace version %d name %q, want %d %q", tc.entity, tc.version(), tc.name(), start+1, "Pertama")
+		}
+		if got := len(auditLog(t, store, tc.entity, tc.id)); got != n {
+			t.Errorf("%s: stale replace wrote %d audit rows", tc.entity, got-n)
+		}
+	}
+}
+
+// Dokumen dari LeadInputFrom/UserDocFrom adalah dasar merge patch: field yang tidak di-patch tetap,
+// field nullable yang di-null-kan dikosongkan.
+func TestPatchDocuments(t *testing.T) {
+	store := memory.NewStore()
+	leads := service.NewLeadService(store)
+	users := service.NewUserService(store)
+
+	lead, err := leads.Create(ctx, service.LeadInput{CompanyName: "PT A", ContactName: "Ani", Email: "ani@a.id",
+		Phone: ptr("0812"), Notes: ptr("catatan")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := service.LeadInputFrom(lead)
+	doc.Phone = nil
+	doc.Region = ptr("Jakarta")
+	got, err := leads.Update(ctx, lead, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Phone != nil || got.Region == nil || *got.Region != "Jakarta" || got.Notes == nil || *got.Notes != "catatan" || got.CompanyName != "PT A" {
+		t.Fatalf("patched lead = %+v", got)
+	}
+	d := diffOf(t, lastAudit(t, store, "lead", lead.LeadID, audit.ActionUpdate))
+	if d["phone"] != (audit.Change{From: "0812", To: nil}) || d["region"] != (audit.Change{From: nil, To: "Jakarta"}) {
+		t.Fatalf("patch diff = %+v", d)
+	}
+	if _, ok := d["notes"]; ok {
+		t.Fatalf("unchanged notes in diff: %+v", d)
+	}
+
+	// Hasil merge tetap divalidasi dengan aturan input biasa.
+	doc = service.LeadInputFrom(got)
+	doc.Email = "bukan-email"
+	var ve *service.ValidationError
+	if _, err := leads.Update(ctx, got, doc); !errors.As(err, &ve) {
+		t.Fatalf("invalid patched email: err = %v, want ValidationError", err)
+	}
+
+	u := addUser(t, store, "Budi", "budi@test.local", "user", models.UserStatusActive)
+	udoc := service.UserDocFrom(u)
+	udoc.Name = "Budi Santoso"
+	updated, err := users.Replace(ctx, u, udoc)
+	if err != nil || updated.Name != "Budi Santoso" || updated.Email != u.Email || updated.Role != u.Role {
+		t.Fatalf("patched user = %+v, %v", updated, err)
+	}
+	udoc.Role = "owner"
+	if _, err := users.Replace(ctx, updated, udoc); !errors.As(err, &ve) {
+		t.Fatalf("invalid patched role: err = %v, want ValidationError", err)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type TrashService struct {
+	store     repository.Store
+	retention time.Duration
+}
+
+// NewTrashService: data di trash boleh dipurge setelah lebih tua dari retention.
+func NewTrashService(store repository.Store, retention time.Duration) *TrashService {
+	return &TrashService{store: store, retention: retention}
+}
+
+// Purge menghapus permanen data trash yang lebih tua dari retention. Return batas waktunya
+// dan jumlah baris terhapus per tabel.
+func (s *TrashService) Purge(ctx context.Context) (time.Time, map[string]int64, error) {
+	before := time.Now().Add(-s.retention)
+	var n map[string]int64
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		if n, err = tx.PurgeTrash(ctx, before); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionPurge, EntityType: "trash",
+			Extra: map[string]interface{}{"purged": n, "before": before}})
+	})
+	return before, n, err
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/audit"
+	"github.com/oktaharis/uji-teknis-godigi/internal/auth"
+	"github.com/oktaharis/uji-teknis-godigi/internal/models"
+	"github.com/oktaharis/uji-teknis-godigi/internal/repository"
+)
+
+type CreateUserInput struct {
+	Name     string `json:"name" binding:"required,min=2"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Role     string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// UpdateUserInput field yang nil tidak diubah.
+type UpdateUserInput struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email" binding:"omitempty,email"`
+	Role  *string `json:"role" binding:"omitempty,oneof=user admin"`
+}
+
+// UserDoc dokumen user yang bisa di-PATCH; semua kolom NOT NULL jadi aturannya mengikuti CreateUserInput.
+type UserDoc struct {
+	Name  string `json:"name" binding:"required,min=2"`
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=user admin"`
+}
+
+func UserDocFrom(u models.User) UserDoc { return UserDoc{Name: u.Name, Email: u.Email, Role: u.Role} }
+
+// DeactivateInput kalau ReassignTo diisi, leads dan projects user dipindah ke user tersebut.
+type DeactivateInput struct {
+	ReassignTo *uint `json:"reassign_to"`
+}
+
+type ReassignInput struct {
+	ToUserID uint `json:"to_user_id" binding:"required"`
+}
+
+// UserService manajemen user oleh admin, termasuk lifecycle (suspend, deactivate, reassign).
+type UserService struct {
+	store repository.Store
+}
+
+func NewUserService(store repository.Store) *UserService { return &UserService{store: store} }
+
+func (s *UserService) Create(ctx context.Context, in CreateUserInput) (models.User, error) {
+	if err := check(in); err != nil {
+		return models.User{}, err
+	}
+	hash, err := auth.HashPassword(in.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+	u := models.User{Name: in.Name, Email: in.Email, PasswordHash: hash, Role: in.Role, Status: models.UserStatusActive}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		err := tx.Users().Create(ctx, &u)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionCreate, EntityType: "user", EntityID: u.ID, After: u})
+	})
+	return u, err
+}
+
+func (s *UserService) List(ctx context.Context, f repository.UserFilter, p repository.Page) ([]models.User, repository.PageInfo, error) {
+	return s.store.Users().List(ctx, f, p)
+}
+
+func (s *UserService) Get(ctx context.Context, id uint) (models.User, error) {
+	u, err := s.store.Users().Get(ctx, id)
+	return u, notFound(err, ErrUserNotFound)
+}
+
+func (s *UserService) Update(ctx context.Context, current models.User, in UpdateUserInput) (models.User, error) {
+	if err := check(in); err != nil {
+		return current, err
+	}
+	u := current
+	if in.Name != nil {
+		u.Name = *in.Name
+	}
+	if in.Email != nil {
+		u.Email = *in.Email
+	}
+	if in.Role != nil {
+		u.Role = *in.Role
+	}
+	return s.save(ctx, current, u)
+}
+
+func (s *UserService) Replace(ctx context.Context, current models.User, doc UserDoc) (models.User, error) {
+	if err := check(doc); err != nil {
+		return current, err
+	}
+	u := current
+	u.Name, u.Email, u.Role = doc.Name, doc.Email, doc.Role
+	return s.save(ctx, current, u)
+}
+
+func (s *UserService) save(ctx context.Context, current, u models.User) (models.User, error) {
+	u.Version = current.Version + 1
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if current.Role == "admin" && u.Role != "admin" {
+			if err := guardLastAdmin(ctx, tx, current.ID); err != nil {
+				return err
+			}
+		}
+		ok, err := tx.Users().UpdateIfVersion(ctx, &u, current.Version)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVersionConflict
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionUpdate, EntityType: "user", EntityID: u.ID,
+			Before: current, After: u})
+	})
+	if err != nil {
+		return current, err
+	}
+	return u, nil
+}
+
+// guardLastAdmin ErrLastAdmin kalau user id adalah satu-satunya admin aktif. Dipanggil di dalam transaksi
+// yang sama dengan write-nya: CountActiveAdmins mengunci baris admin aktif sampai commit, jadi dua request
+// yang menonaktifkan admin berbeda tidak bisa sama-sama lolos. Role dan status dibaca ulang setelah lock
+// supaya tidak memakai data user yang basi.
+func guardLastAdmin(ctx context.Context, tx repository.Store, id uint) error {
+	others, err := tx.Users().CountActiveAdmins(ctx, id)
+	if err != nil || others > 0 {
+		return err
+	}
+	u, err := tx.Users().Get(ctx, id)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	if u.Role == "admin" && u.IsActive() {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// Delete memindahkan user ke trash. Kepemilikan project dibiarkan supaya restore tidak kehilangan data,
+// owner_user_id baru dilepas saat user dipurge. Return user sebelum dihapus.
+func (s *UserService) Delete(ctx context.Context, id uint) (models.User, error) {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return u, err
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := guardLastAdmin(ctx, tx, u.ID); err != nil {
+			return err
+		}
+		ok, err := tx.Users().SoftDelete(ctx, u.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrUserNotFound
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionDelete, EntityType: "user", EntityID: u.ID, Before: u})
+	})
+	return u, err
+}
+
+func (s *UserService) Trash(ctx context.Context, p repository.Page) ([]models.User, int64, error) {
+	return s.store.Users().ListTrashed(ctx, p)
+}
+
+func (s *UserService) Restore(ctx context.Context, id uint) (models.User, error) {
+	u, err := s.store.Users().GetTrashed(ctx, id)
+	if err != nil {
+		return u, notFound(err, ErrUserNotInTrash)
+	}
+	restored := u
+	restored.DeletedAt = gorm.DeletedAt{}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		err := tx.Users().Restore(ctx, u.ID)
+		if errors.Is(err, repository.ErrDuplicate) {
+			// email-nya sudah dipakai user lain sejak user ini masuk trash
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionRestore, EntityType: "user", EntityID: u.ID,
+			Before: u, After: restored})
+	})
+	if err != nil {
+		return u, err
+	}
+	return restored, nil
+}
+
+func (s *UserService) Suspend(ctx context.Context, u models.User) (models.User, error) {
+	out := u
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := guardLastAdmin(ctx, tx, u.ID); err != nil {
+			return err
+		}
+		var err error
+		out, err = setStatus(ctx, tx, u, models.UserStatusSuspended, audit.ActionSuspend, nil)
+		return err
+	})
+	if err != nil {
+		return u, err
+	}
+	return out, nil
+}
+
+// Deactivate menonaktifkan user; kalau in.ReassignTo diisi, leads dan projects miliknya dipindah dulu
+// dalam transaksi yang sama.
+func (s *UserService) Deactivate(ctx context.Context, u models.User, in DeactivateInput) (models.User, error) {
+	reassignTo := in.ReassignTo
+	out := u
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := guardLastAdmin(ctx, tx, u.ID); err != nil {
+			return err
+		}
+		if reassignTo != nil {
+			if err := reassignOwnership(ctx, tx, u, *reassignTo); err != nil {
+				return err
+			}
+		}
+		var err error
+		out, err = setStatus(ctx, tx, u, models.UserStatusDeactivated, audit.ActionDeactivate,
+			map[string]interface{}{"reassign_to": reassignTo})
+		return err
+	})
+	if err != nil {
+		return u, err
+	}
+	return out, nil
+}
+
+func (s *UserService) Reactivate(ctx context.Context, u models.User) (models.User, error) {
+	out := u
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		out, err = setStatus(ctx, tx, u, models.UserStatusActive, audit.ActionReactivate, nil)
+		return err
+	})
+	if err != nil {
+		return u, err
+	}
+	return out, nil
+}
+
+// ForceLogout me-revoke semua token user.
+func (s *UserService) ForceLogout(ctx context.Context, u models.User) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		return revokeTokens(ctx, tx, u.ID, audit.ActionForceLogout)
+	})
+}
+
+// ResetPassword me-revoke semua sesi user dan membuat token reset baru.
+func (s *UserService) ResetPassword(ctx context.Context, u models.User) (string, error) {
+	var token string
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := revokeTokens(ctx, tx, u.ID, audit.ActionResetPassword); err != nil {
+			return err
+		}
+		var err error
+		token, err = issuePasswordReset(ctx, tx, u.ID)
+		return err
+	})
+	return token, err
+}
+
+// Reassign memindahkan leads dan projects milik u ke user lain.
+func (s *UserService) Reassign(ctx context.Context, u models.User, in ReassignInput) error {
+	if err := check(in); err != nil {
+		return err
+	}
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := reassignOwnership(ctx, tx, u, in.ToUserID); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.Entry{Action: audit.ActionReassign, EntityType: "user", EntityID: u.ID,
+			Extra: map[string]interface{}{"to_user_id": in.ToUserID}})
+	})
+}
+
+// reassignOwnership memindahkan leads (sales_rep) dan projects (owner_user_id) dari user `from` ke user `toID`.
+// sales_rep lead berisi nama, bukan id user, jadi kalau nama `from` atau `to` dipakai user lain
+// pemindahan ditolak dengan ErrSalesRepAmbiguous supaya lead user lain tidak ikut pindah.
+func reassignOwnership(ctx context.Context, tx repository.Store, from models.User, toID uint) error {
+	to, err := tx.Users().Get(ctx, toID)
+	if err != nil || !to.IsActive() || to.ID == from.ID {
+		return ErrReassignTarget
+	}
+	for _, name := range []string{from.Name, to.Name} {
+		n, err := tx.Users().CountByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if n > 1 {
+			return ErrSalesRepAmbiguous
+		}
+	}
+	if err := tx.Leads().ReassignSalesRep(ctx, from.Name, to.Name); err != nil {
+		return err
+	}
+	return tx.Projects().ReassignOwner(ctx, from.ID, to.ID)
+}
+
+// setStatus mengubah status user di tx dan mencatatnya dengan action tersebut; token_version ikut naik
+// supaya semua token lama langsung invalid.
+func setStatus(ctx context.Context, tx repository.Store, u models.User, status, action string, extra map[string]interface{}) (models.User, error) {
+	if err := tx.Users().SetStatus(ctx, u.ID, status); err != nil {
+		return u, err
+	}
+	out := u
+	out.Status = status
+	out.TokenVersion++
+	out.Version++
+	err := record(ctx, tx, audit.Entry{Action: action, EntityType: "user", EntityID: u.ID, Before: u, After: out, Extra: extra})
+	return out, err
+}
@@ -0,0 +1,86 @@
+// Package tracing OpenTelemetry untuk API: span per request (Middleware), span anak per statement GORM
+// (InstrumentDB) dan propagasi W3C trace-context. Exporter dipilih lewat cfg.TraceExporter.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	// scope nama instrumentation untuk semua span dari aplikasi ini
+	scope = "github.com/oktaharis/uji-teknis-godigi"
+
+	defaultServiceName = "godigi-api"
+)
+
+// Setup memasang propagator W3C (traceparent + baggage) dan, kalau exporter bukan "none", TracerProvider
+// global dengan sampler ParentBased(TraceIDRatioBased(cfg.TraceSampleRate)). stdout menulis span ke w.
+// OTLP memakai variabel standar OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_HEADERS.
+// Fungsi shutdown mem-flush span yang masih di buffer; panggil sebelum proses keluar.
+func Setup(ctx context.Context, cfg *config.Config, w io.Writer) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exp sdktrace.SpanExporter
+	switch cfg.TraceExporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		exp, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q (want none, stdout or otlp)", cfg.TraceExporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create %s exporter: %w", cfg.TraceExporter, err)
+	}
+
+	// OTEL_SERVICE_NAME / OTEL_RESOURCE_ATTRIBUTES tetap bisa menimpa nilai default di bawah.
+	res, err := resource.Merge(
+		resource.NewSchemaless(
+			attribute.String("service.name", defaultServiceName),
+			attribute.String("deployment.environment", cfg.AppEnv),
+		),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRate))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer tracer aplikasi. Diambil dari provider global setiap kali dipanggil supaya ikut provider yang
+// dipasang belakangan (mis. di test).
+func Tracer() trace.Tracer { return otel.Tracer(scope) }
+
+// TraceID trace id dari span di ctx, kosong kalau tidak ada span yang valid.
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/oktaharis/uji-teknis-godigi/internal/config"
+)
+
+func TestSetupStdout(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	var buf bytes.Buffer
+	shutdown, err := Setup(context.Background(), &config.Config{AppEnv: "test", TraceExporter: ExporterStdout, TraceSampleRate: 1}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, span := Tracer().Start(context.Background(), "unit")
+	if TraceID(ctx) == "" {
+		t.Fatal("TraceID empty for a sampled span")
+	}
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"Name":"unit"`) || !strings.Contains(out, "godigi-api") {
+		t.Fatalf("stdout exporter output = %s", out)
+	}
+}
+
+func TestSetupSampleRateZero(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	var buf bytes.Buffer
+	shutdown, err := Setup(context.Background(), &config.Config{TraceExporter: ExporterStdout, TraceSampleRate: 0}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := Tracer().Start(context.Background(), "dropped")
+	span.End()
+	_ = shutdown(context.Background())
+	if buf.Len() != 0 {
+		t.Fatalf("span exported with sample rate 0: %s", buf.String())
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), &config.Config{TraceExporter: "jaeger"}, nil); err == nil {
+		t.Fatal("want error for unknown exporter")
+	}
+	if TraceID(context.Background()) != "" {
+		t.Fatal("TraceID without span should be empty")
+	}
+}